@@ -19,7 +19,7 @@ import (
 //		Usage:  "List containers",
 //		Action: app.WithProject(factory, app.ProjectPs),
 //	}
-type ProjectAction func(project *project.Project, c *cli.Context)
+type ProjectAction func(project *project.Project, c *cli.Context) error
 
 // BeforeApp is an action that is executed before any cli command.
 func BeforeApp(c *cli.Context) error {
@@ -31,41 +31,45 @@ func BeforeApp(c *cli.Context) error {
 }
 
 // WithProject is an helper function to create a cli.Command action with a ProjectFactory.
+// The command exits with the code of the error of the action, see ExitCode.
 func WithProject(factory ProjectFactory, action ProjectAction) func(context *cli.Context) {
 	return func(context *cli.Context) {
 		p, err := factory.Create(context)
 		if err != nil {
-			logrus.Fatalf("Failed to read project: %v", err)
+			os.Exit(HandleError(&ExitError{Code: ExitProject, Err: fmt.Errorf("Failed to read project: %v", err)}))
+		}
+		if err := action(p, context); err != nil {
+			os.Exit(HandleError(err))
 		}
-		action(p, context)
 	}
 }
 
 // ProjectPs lists the containers.
-func ProjectPs(p *project.Project, c *cli.Context) {
+func ProjectPs(p *project.Project, c *cli.Context) error {
 	allInfo := project.InfoSet{}
 	qFlag := c.Bool("q")
 	for name := range p.Configs {
 		service, err := p.CreateService(name)
 		if err != nil {
-			logrus.Fatal(err)
+			return err
 		}
 
 		info, err := service.Info(qFlag)
 		if err != nil {
-			logrus.Fatal(err)
+			return &project.ServiceError{Service: name, Err: err}
 		}
 
 		allInfo = append(allInfo, info...)
 	}
 
 	os.Stdout.WriteString(allInfo.String(!qFlag))
+	return nil
 }
 
 // ProjectPort prints the public port for a port binding.
-func ProjectPort(p *project.Project, c *cli.Context) {
+func ProjectPort(p *project.Project, c *cli.Context) error {
 	if len(c.Args()) != 2 {
-		logrus.Fatalf("Please pass arguments in the form: SERVICE PORT")
+		return NewUsageError("Please pass arguments in the form: SERVICE PORT")
 	}
 
 	index := c.Int("index")
@@ -73,131 +77,103 @@ func ProjectPort(p *project.Project, c *cli.Context) {
 
 	service, err := p.CreateService(c.Args()[0])
 	if err != nil {
-		logrus.Fatal(err)
+		return err
 	}
 
 	containers, err := service.Containers()
 	if err != nil {
-		logrus.Fatal(err)
+		return &project.ServiceError{Service: service.Name(), Err: err}
 	}
 
 	if index < 1 || index > len(containers) {
-		logrus.Fatalf("Invalid index %d", index)
+		return NewUsageError("Invalid index %d", index)
 	}
 
 	output, err := containers[index-1].Port(fmt.Sprintf("%s/%s", c.Args()[1], protocol))
 	if err != nil {
-		logrus.Fatal(err)
+		return &project.ServiceError{Service: service.Name(), Err: err}
 	}
 	fmt.Println(output)
+	return nil
 }
 
 // ProjectDown brings all services down.
-func ProjectDown(p *project.Project, c *cli.Context) {
-	err := p.Down(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectDown(p *project.Project, c *cli.Context) error {
+	return p.Down(c.Args()...)
 }
 
 // ProjectBuild builds or rebuilds services.
-func ProjectBuild(p *project.Project, c *cli.Context) {
-	err := p.Build(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectBuild(p *project.Project, c *cli.Context) error {
+	return p.Build(c.Args()...)
 }
 
 // ProjectCreate creates all services but do not start them.
-func ProjectCreate(p *project.Project, c *cli.Context) {
-	err := p.Create(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectCreate(p *project.Project, c *cli.Context) error {
+	return p.Create(c.Args()...)
 }
 
 // ProjectUp brings all services up.
-func ProjectUp(p *project.Project, c *cli.Context) {
-	err := p.Up(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
+func ProjectUp(p *project.Project, c *cli.Context) error {
+	if err := p.Up(c.Args()...); err != nil {
+		return err
 	}
 
 	if !c.Bool("d") {
 		wait()
 	}
+	return nil
 }
 
 // ProjectStart starts services.
-func ProjectStart(p *project.Project, c *cli.Context) {
-	err := p.Start(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectStart(p *project.Project, c *cli.Context) error {
+	return p.Start(c.Args()...)
 }
 
 // ProjectRestart restarts services.
-func ProjectRestart(p *project.Project, c *cli.Context) {
-	err := p.Restart(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectRestart(p *project.Project, c *cli.Context) error {
+	return p.Restart(c.Args()...)
 }
 
 // ProjectLog gets services logs.
-func ProjectLog(p *project.Project, c *cli.Context) {
-	err := p.Log(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
+func ProjectLog(p *project.Project, c *cli.Context) error {
+	if err := p.Log(c.Args()...); err != nil {
+		return err
 	}
 	wait()
+	return nil
 }
 
 // ProjectPull pulls images for services.
-func ProjectPull(p *project.Project, c *cli.Context) {
-	err := p.Pull(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectPull(p *project.Project, c *cli.Context) error {
+	return p.Pull(c.Args()...)
 }
 
 // ProjectDelete delete services.
-func ProjectDelete(p *project.Project, c *cli.Context) {
+func ProjectDelete(p *project.Project, c *cli.Context) error {
 	if !c.Bool("force") && len(c.Args()) == 0 {
-		logrus.Fatal("Will not remove all services without --force")
-	}
-	err := p.Delete(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
+		return NewUsageError("Will not remove all services without --force")
 	}
+	return p.Delete(c.Args()...)
 }
 
 // ProjectKill forces stop service containers.
-func ProjectKill(p *project.Project, c *cli.Context) {
-	err := p.Kill(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectKill(p *project.Project, c *cli.Context) error {
+	return p.Kill(c.Args()...)
 }
 
 // ProjectPause pauses service containers.
-func ProjectPause(p *project.Project, c *cli.Context) {
-	err := p.Pause(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectPause(p *project.Project, c *cli.Context) error {
+	return p.Pause(c.Args()...)
 }
 
 // ProjectUnpause unpauses service containers.
-func ProjectUnpause(p *project.Project, c *cli.Context) {
-	err := p.Unpause(c.Args()...)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+func ProjectUnpause(p *project.Project, c *cli.Context) error {
+	return p.Unpause(c.Args()...)
 }
 
-// ProjectScale scales services.
-func ProjectScale(p *project.Project, c *cli.Context) {
+// ProjectScale scales services. The arguments are all checked before scaling
+// any service, a failure to scale a service does not stop the other ones.
+func ProjectScale(p *project.Project, c *cli.Context) error {
 	// This code is a bit verbose but I wanted to parse everything up front
 	order := make([]string, 0, 0)
 	serviceScale := make(map[string]int)
@@ -206,23 +182,19 @@ func ProjectScale(p *project.Project, c *cli.Context) {
 	for _, arg := range c.Args() {
 		kv := strings.SplitN(arg, "=", 2)
 		if len(kv) != 2 {
-			logrus.Fatalf("Invalid scale parameter: %s", arg)
+			return NewUsageError("Invalid scale parameter: %s", arg)
 		}
 
 		name := kv[0]
 
 		count, err := strconv.Atoi(kv[1])
 		if err != nil {
-			logrus.Fatalf("Invalid scale parameter: %v", err)
-		}
-
-		if _, ok := p.Configs[name]; !ok {
-			logrus.Fatalf("%s is not defined in the template", name)
+			return NewUsageError("Invalid scale parameter: %v", err)
 		}
 
 		service, err := p.CreateService(name)
 		if err != nil {
-			logrus.Fatalf("Failed to lookup service: %s: %v", service, err)
+			return err
 		}
 
 		order = append(order, name)
@@ -230,14 +202,16 @@ func ProjectScale(p *project.Project, c *cli.Context) {
 		services[name] = service
 	}
 
+	results := map[string]error{}
 	for _, name := range order {
 		scale := serviceScale[name]
 		logrus.Infof("Setting scale %s=%d...", name, scale)
-		err := services[name].Scale(scale)
-		if err != nil {
-			logrus.Fatalf("Failed to set the scale %s=%d: %v", name, scale, err)
+		results[name] = services[name].Scale(scale)
+		if results[name] != nil {
+			logrus.Errorf("Failed to set the scale %s=%d: %v", name, scale, results[name])
 		}
 	}
+	return project.NewActionError(results)
 }
 
 func wait() {
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/project"
+)
+
+// BackendFactory is a ProjectFactory delegating to the factory of the backend
+// selected with the global backend flag (docker, kubernetes, …).
+type BackendFactory map[string]ProjectFactory
+
+// Create implements ProjectFactory.Create using the factory of the selected backend.
+func (f BackendFactory) Create(c *cli.Context) (*project.Project, error) {
+	backend := c.GlobalString("backend")
+
+	factory, ok := f[backend]
+	if !ok {
+		return nil, fmt.Errorf("Unknown backend %s", backend)
+	}
+
+	return factory.Create(c)
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
+)
+
+// ProjectConvert converts the project to the artifacts of the target selected
+// with --to and writes them to the output directory.
+func ProjectConvert(p *project.Project, c *cli.Context) error {
+	format := c.String("format")
+	if c.Bool("yaml") {
+		format = "yaml"
+	}
+
+	options := transformer.Options{
+		Format:          format,
+		Deployment:      c.Bool("deployment"),
+		WaitForDeps:     c.Bool("wait-for-deps"),
+		NetworkPolicies: c.Bool("network-policies"),
+		PullSecrets:     c.Bool("pull-secrets"),
+		ConfigDir:       c.GlobalString("configdir"),
+	}
+
+	artifacts, err := transformer.Convert(p, c.String("to"), options)
+	if err != nil {
+		return err
+	}
+
+	if err := transformer.Write(c.String("out"), artifacts); err != nil {
+		return fmt.Errorf("Failed to write the artifacts of project %s: %v", p.Name, err)
+	}
+	return nil
+}
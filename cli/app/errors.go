@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+)
+
+// Exit codes of the commands.
+const (
+	ExitSuccess         = 0
+	ExitFailure         = 1
+	ExitUsage           = 2
+	ExitValidation      = 3
+	ExitServiceNotFound = 4
+	ExitProject         = 5
+)
+
+// UsageError is the error of a command called with invalid arguments.
+type UsageError struct {
+	Message string
+}
+
+// NewUsageError returns a usage error with a message formatted according to
+// the format specifier.
+func NewUsageError(format string, args ...interface{}) *UsageError {
+	return &UsageError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *UsageError) Error() string {
+	return e.Message
+}
+
+// ExitError is an error with the exit code of the command. Err may be nil
+// when the exit code is the whole result, like with diff.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("Exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// ExitCode returns the exit code of a command failing with the error. A
+// failure on several services has the code of their errors if they all have
+// the same one, ExitFailure otherwise.
+func ExitCode(err error) int {
+	switch e := err.(type) {
+	case nil:
+		return ExitSuccess
+	case *ExitError:
+		return e.Code
+	case *UsageError:
+		return ExitUsage
+	case *project.ValidationError:
+		return ExitValidation
+	case *project.ServiceError:
+		if project.IsServiceNotFound(e) {
+			return ExitServiceNotFound
+		}
+		return ExitCode(e.Err)
+	case *project.ActionError:
+		code := ExitFailure
+		for i, failed := range e.Failed {
+			if i == 0 {
+				code = ExitCode(failed)
+			} else if ExitCode(failed) != code {
+				return ExitFailure
+			}
+		}
+		return code
+	}
+	return ExitFailure
+}
+
+// HandleError logs the error of a command, along with the result of each
+// service when it failed on some of them, and returns its exit code.
+func HandleError(err error) int {
+	return handleError(os.Stderr, err)
+}
+
+func handleError(w io.Writer, err error) int {
+	switch e := err.(type) {
+	case nil:
+		return ExitSuccess
+	case *ExitError:
+		if e.Err != nil {
+			logrus.Error(e.Err)
+		}
+	case *project.ActionError:
+		fmt.Fprint(w, e.Summary().String(true))
+		logrus.Errorf("Failed on %d of %d services", len(e.Failed), len(e.Failed)+len(e.Succeeded))
+	default:
+		logrus.Error(err)
+	}
+	return ExitCode(err)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	invalid := project.NewValidationError("web", "restart", "unknown restart policy %s", "sometimes")
+	failure := errors.New("Failed")
+
+	for code, err := range map[int]error{
+		ExitSuccess:         nil,
+		ExitFailure:         failure,
+		ExitUsage:           NewUsageError("Invalid scale parameter: %s", "web"),
+		ExitValidation:      invalid,
+		ExitServiceNotFound: project.NewServiceNotFound("db"),
+		ExitProject:         &ExitError{Code: ExitProject, Err: failure},
+	} {
+		assert.Equal(t, code, ExitCode(err), "%v", err)
+	}
+
+	// Failures on several services have their common code.
+	assert.Equal(t, ExitValidation, ExitCode(project.NewActionError(map[string]error{
+		"web":   invalid,
+		"redis": nil,
+	})))
+	assert.Equal(t, ExitFailure, ExitCode(project.NewActionError(map[string]error{
+		"web":   invalid,
+		"redis": failure,
+	})))
+}
+
+func TestHandleActionError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	code := handleError(buf, project.NewActionError(map[string]error{
+		"web":   errors.New("No controller found"),
+		"redis": nil,
+	}))
+
+	assert.Equal(t, ExitFailure, code)
+	assert.Contains(t, buf.String(), "redis")
+	assert.Contains(t, buf.String(), "No controller found")
+}
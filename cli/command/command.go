@@ -1,10 +1,13 @@
 package command
 
 import (
+	"strings"
+
 	"github.com/codegangsta/cli"
 	"github.com/docker/libcompose/cli/app"
 	k8sApp "github.com/docker/libcompose/cli/k8s/app"
 	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
 )
 
 // CreateCommand defines the libcompose create subcommand.
@@ -214,82 +217,266 @@ func KillCommand(factory app.ProjectFactory) cli.Command {
 	}
 }
 
+// ConvertCommand defines the kompose convert subcommand.
+func ConvertCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "convert",
+		Usage:  "Convert the compose file to the objects of another orchestrator",
+		Action: app.WithProject(factory, app.ProjectConvert),
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "Target orchestrator: " + strings.Join(transformer.Targets(), ", "),
+				Value: "kubernetes",
+			},
+			cli.StringFlag{
+				Name:  "out,o",
+				Usage: "Directory the generated files are written to",
+				Value: ".",
+			},
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format, json or yaml for kubernetes, hcl or json for nomad (default: the first one)",
+			},
+			cli.BoolFlag{
+				Name:  "yaml,y",
+				Usage: "Generate yaml, same as --format yaml",
+			},
+			cli.BoolFlag{
+				Name:  "deployment,d",
+				Usage: "Generate deployments rather than replication controllers (kubernetes)",
+			},
+			cli.BoolFlag{
+				Name:  "pull-secrets",
+				Usage: "Generate the credentials of the private registries from the docker configuration (kubernetes)",
+			},
+			cli.BoolFlag{
+				Name:  "wait-for-deps",
+				Usage: "Delay the start of services until their links accept connections (kubernetes)",
+			},
+			cli.BoolFlag{
+				Name:  "network-policies",
+				Usage: "Only allow connections to services from the services linking to them (kubernetes)",
+			},
+		},
+	}
+}
+
 func KuberCommand(factory app.ProjectFactory) cli.Command {
 	return cli.Command{
-		Name:   "k8s",
-		Usage:  "Kubernetes specific commands",
-		Subcommands: []cli.Command {
+		Name:  "k8s",
+		Usage: "Kubernetes specific commands",
+		Subcommands: []cli.Command{
 			{
-				Name:	"convert",
-				Usage:	"Convert docker-compose.yml to Kubernetes object and submit",
+				Name:   "convert",
+				Usage:  "Convert docker-compose.yml to Kubernetes object and submit",
 				Action: app.WithProject(factory, k8sApp.ProjectKuber),
 				Flags: []cli.Flag{
 					cli.StringFlag{
-						Name:  "file,f",
-						Usage: "Specify an alternate compose file (default: docker-compose.yml)",
-						Value:	"docker-compose.yml",
+						Name:   "file,f",
+						Usage:  "Specify an alternate compose file (default: docker-compose.yml)",
+						Value:  "docker-compose.yml",
 						EnvVar: "COMPOSE_FILE",
 					},
 					cli.BoolFlag{
-						Name:	"deployment,d",
-						Usage:	"Generate a deployment resource file",
+						Name:  "deployment,d",
+						Usage: "Generate a deployment resource file",
 					},
 					cli.BoolFlag{
-						Name:	"chart,c",
-						Usage:	"Create a chart deployment",
+						Name:  "chart,c",
+						Usage: "Create a chart deployment",
 					},
 					cli.BoolFlag{
-						Name: "yaml, y",
+						Name:  "yaml, y",
 						Usage: "Generate a deployment resource file in yaml format",
 					},
+					cli.BoolFlag{
+						Name:  "pull-secrets",
+						Usage: "Generate an image pull secret from the docker configuration for the registries of the images",
+					},
+					cli.BoolFlag{
+						Name:  "wait-for-deps",
+						Usage: "Add init containers waiting for the ports of the linked services to accept connections",
+					},
+					cli.BoolFlag{
+						Name:  "network-policies",
+						Usage: "Generate network policies only allowing connections from linking services and to published ports",
+					},
+				},
+			},
+			{
+				Name:   "up",
+				Usage:  "Create or update the kubernetes objects of the services",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberUp),
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "watch,w",
+						Usage: "Apply the objects of k8s convert, then watch the compose files and apply their changes",
+					},
+					cli.BoolFlag{
+						Name:  "deployment,d",
+						Usage: "With --watch, apply deployments rather than replication controllers",
+					},
+					cli.BoolFlag{
+						Name:  "pull-secrets",
+						Usage: "With --watch, apply an image pull secret generated from the docker configuration",
+					},
+					cli.BoolFlag{
+						Name:  "wait-for-deps",
+						Usage: "With --watch, add init containers waiting for the ports of the linked services",
+					},
+					cli.BoolFlag{
+						Name:  "network-policies",
+						Usage: "With --watch, apply network policies only allowing connections from linking services",
+					},
+				},
+			},
+			{
+				Name:   "diff",
+				Usage:  "Show the differences between the cluster and the compose file, exit with 1 if they differ",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberDiff),
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "deployment,d",
+						Usage: "Compare deployments rather than replication controllers",
+					},
+					cli.BoolFlag{
+						Name:  "pull-secrets",
+						Usage: "Compare the image pull secret generated from the docker configuration",
+					},
+					cli.BoolFlag{
+						Name:  "wait-for-deps",
+						Usage: "Compare the init containers waiting for the ports of the linked services",
+					},
+					cli.BoolFlag{
+						Name:  "network-policies",
+						Usage: "Compare the network policies of the services",
+					},
 				},
 			},
 			{
-				Name:	"ps",
-				Usage:	"Get active data in the kubernetes cluster",
-				Action:	app.WithProject(factory, k8sApp.ProjectKuberPS),
-				Flags: []cli.Flag {
-					cli.BoolFlag {
-						Name:	"service,svc",
-						Usage:	"Get active services",
-					},
-					cli.BoolFlag {
-						Name:	"replicationcontroller,rc",
-						Usage:	"Get active replication controller",
+				Name:   "ps",
+				Usage:  "List the controllers, pods and endpoints of each service",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberPS),
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "output,o",
+						Usage: "Output format: json, yaml or wide",
+					},
+					cli.StringFlag{
+						Name:  "format",
+						Usage: "Pretty-print services using a Go template",
 					},
 				},
 			},
 			{
-				Name:	"delete",
-				Usage:	"Remove instantiated services/rc from kubernetes",
-				Action:	app.WithProject(factory, k8sApp.ProjectKuberDelete),
-				Flags:	[]cli.Flag {
-					cli.BoolFlag {
-						Name: "replicationcontroller,rc",
+				Name:   "delete",
+				Usage:  "Remove instantiated services/rc from kubernetes",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberDelete),
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "replicationcontroller,rc",
 						Usage: "Remove active replication controllers",
 					},
-					cli.BoolFlag {
-						Name: "service,svc",
+					cli.BoolFlag{
+						Name:  "service,svc",
 						Usage: "Remove active services",
 					},
-					cli.StringFlag {
-						Name:	"name",
-						Usage:	"Name of the object to remove",
+					cli.StringFlag{
+						Name:  "name",
+						Usage: "Name of the object to remove",
+					},
+					cli.BoolFlag{
+						Name:  "remove-orphans",
+						Usage: "Remove objects for services not defined in the compose file",
+					},
+				},
+			},
+			{
+				Name:   "down",
+				Usage:  "Scale down and remove all kubernetes objects of the project",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberDown),
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "yes,y",
+						Usage: "Do not ask for confirmation",
+					},
+					cli.BoolFlag{
+						Name:  "volumes,v",
+						Usage: "Remove persistent volume claims of the project",
+					},
+					cli.IntFlag{
+						Name:  "timeout,t",
+						Usage: "Specify how long to wait for pods to terminate, in seconds",
+						Value: 60,
+					},
+					cli.IntFlag{
+						Name:  "grace-period",
+						Usage: "Override the termination grace period of the pods, in seconds",
 					},
 				},
 			},
 			{
-				Name:	"scale",
-				Usage:	"Globally scale instantiated replication controllers",
-				Action:	app.WithProject(factory, k8sApp.ProjectKuberScale),
-				Flags:	[]cli.Flag {
-					cli.IntFlag {
-						Name:	"scale",
-						Usage:	"New number of replicas",
-					},
-					cli.StringFlag {
-						Name:	"replicationcontroller,rc",
-						Usage:	"A specific replication controller to scale",
+				Name:   "scale",
+				Usage:  "Scale services, given as SERVICE=NUM arguments",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberScale),
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "wait,w",
+						Usage: "Wait until the requested number of pods are ready",
+					},
+					cli.IntFlag{
+						Name:  "timeout,t",
+						Usage: "Specify how long to wait for pods to be ready, in seconds",
+						Value: 300,
+					},
+				},
+			},
+			{
+				Name:   "logs",
+				Usage:  "Stream the logs of the pods of services",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberLogs),
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "follow,f",
+						Usage: "Follow log output",
+					},
+					cli.StringFlag{
+						Name:  "tail",
+						Usage: "Number of lines to show from the end of the logs",
+						Value: "all",
+					},
+					cli.StringFlag{
+						Name:  "since",
+						Usage: "Show logs newer than a relative duration like 10m or 1h",
+					},
+				},
+			},
+			{
+				Name:   "status",
+				Usage:  "Watch the rollout of services until their pods are updated and available",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberStatus),
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "timeout,t",
+						Usage: "Specify how long to wait for the rollout, in seconds",
+						Value: 300,
+					},
+				},
+			},
+			{
+				Name:   "history",
+				Usage:  "List the revisions of the deployments of services",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberHistory),
+			},
+			{
+				Name:   "rollback",
+				Usage:  "Roll the deployment of a service back to a previous revision",
+				Action: app.WithProject(factory, k8sApp.ProjectKuberRollback),
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "to-revision",
+						Usage: "Revision to roll back to, the previous one if 0",
 					},
 				},
 			},
@@ -1,7 +1,8 @@
 package app
 
 import (
-	"github.com/Sirupsen/logrus"
+	"fmt"
+
 	"github.com/codegangsta/cli"
 	"github.com/docker/libcompose/docker"
 )
@@ -39,7 +40,8 @@ func DockerClientFlags() []cli.Flag {
 }
 
 // Populate updates the specified docker context based on command line arguments and subcommands.
-func Populate(context *docker.Context, c *cli.Context) {
+// It fails if the docker client cannot be configured, like with invalid TLS files.
+func Populate(context *docker.Context, c *cli.Context) error {
 	context.ConfigDir = c.String("configdir")
 
 	opts := docker.ClientOpts{}
@@ -51,8 +53,9 @@ func Populate(context *docker.Context, c *cli.Context) {
 
 	clientFactory, err := docker.NewDefaultClientFactory(opts)
 	if err != nil {
-		logrus.Fatalf("Failed to construct Docker client: %v", err)
+		return fmt.Errorf("Failed to construct Docker client: %v", err)
 	}
 
 	context.ClientFactory = clientFactory
+	return nil
 }
@@ -16,7 +16,9 @@ type ProjectFactory struct {
 func (p *ProjectFactory) Create(c *cli.Context) (*project.Project, error) {
 	context := &docker.Context{}
 	context.LoggerFactory = logger.NewColorLoggerFactory()
-	Populate(context, c)
+	if err := Populate(context, c); err != nil {
+		return nil, err
+	}
 	command.Populate(&context.Context, c)
 
 	return docker.NewProject(context)
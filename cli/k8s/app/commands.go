@@ -17,496 +17,327 @@ limitations under the License.
 package app
 
 import (
-    "fmt"
-    "strconv"
-    "strings"
+	"fmt"
 
-    "github.com/Sirupsen/logrus"
-    "github.com/codegangsta/cli"
+	"github.com/codegangsta/cli"
 
-    "github.com/docker/libcompose/project"
+	cliApp "github.com/docker/libcompose/cli/app"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
 
-    "encoding/json"
-    "io/ioutil"
+	"encoding/json"
+	"io/ioutil"
+	"os"
 
-    "k8s.io/kubernetes/pkg/api"
-    "k8s.io/kubernetes/pkg/apis/extensions"
-    "k8s.io/kubernetes/pkg/util"
-    "k8s.io/kubernetes/pkg/api/unversioned"
-    client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
 
-    "github.com/ghodss/yaml"
+	"github.com/ghodss/yaml"
 )
 
 /* Kubernetes specific configuration */
 
-func ProjectKuberConfig(p *project.Project, c *cli.Context) {
-    url := c.String("host")
+func ProjectKuberConfig(p *project.Project, c *cli.Context) error {
+	url := c.String("host")
 
-    outputFilePath := ".kuberconfig"
-    wurl := []byte(url)
-    if err := ioutil.WriteFile(outputFilePath, wurl, 0644); err != nil {
-        logrus.Fatalf("Failed to write k8s api server address to %s: %v", outputFilePath, err)
-    }
+	outputFilePath := ".kuberconfig"
+	wurl := []byte(url)
+	if err := ioutil.WriteFile(outputFilePath, wurl, 0644); err != nil {
+		return fmt.Errorf("Failed to write k8s api server address to %s: %v", outputFilePath, err)
+	}
+	return nil
 }
 
-func ProjectKuberPS(p *project.Project, c *cli.Context) {
-    server := getK8sServer("")
-    version := "v1"
+func ProjectKuberPS(p *project.Project, c *cli.Context) error {
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
 
-    client := client.NewOrDie(&client.Config{Host: server, Version: version})
-	if c.BoolT("svc") {
-        fmt.Printf("%-20s%-20s%-20s%-20s\n","Name", "Cluster IP", "Ports", "Selectors")
-        for name := range p.Configs {
-            var ports string
-            var selectors string
-            services, err := client.Services(api.NamespaceDefault).Get(name)
+	statuses, err := collectStatus(client, p)
+	if err != nil {
+		return fmt.Errorf("Cannot retrieve the state of project %s: %v", p.Name, err)
+	}
 
-            if err != nil {
-                logrus.Debugf("Cannot find service for: ", name)
-            } else {
+	output, err := statuses.Format(c.String("output"), c.String("format"))
+	if err != nil {
+		return cliApp.NewUsageError("%v", err)
+	}
 
-                for i := range services.Spec.Ports {
-                    p := strconv.Itoa(services.Spec.Ports[i].Port)
-                    ports += ports + string(services.Spec.Ports[i].Protocol) + "(" + p + "),"
-                }
+	os.Stdout.WriteString(output)
+	return nil
+}
 
-                for k,v := range services.ObjectMeta.Labels {
-                    selectors += selectors + k + "=" + v + ","
-                }
+func ProjectKuberDelete(p *project.Project, c *cli.Context) error {
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
 
-                ports = strings.TrimSuffix(ports, ",")
-                selectors = strings.TrimSuffix(selectors, ",")
+	name := c.String("name")
+	if len(name) > 0 {
+		if _, ok := p.Configs[name]; !ok {
+			return project.NewServiceNotFound(name)
+		}
+	}
 
-                fmt.Printf("%-20s%-20s%-20s%-20s\n", services.ObjectMeta.Name,
-                    services.Spec.ClusterIP, ports, selectors)
-            }
+	results := map[string]error{}
+	selectors := map[string]labels.Selector{}
+	for service := range p.Configs {
+		if len(name) > 0 && service != name {
+			continue
+		}
+		selectors[service] = kubernetes.ServiceSelector(p, service)
+	}
 
-        }
+	if c.Bool("remove-orphans") {
+		selectors[""] = kubernetes.OrphanSelector(p)
 	}
 
-	if c.BoolT("rc") {
-        fmt.Printf("%-15s%-15s%-30s%-10s%-20s\n", "Name", "Containers", "Images",
-            "Replicas", "Selectors")
-        for name := range p.Configs {
-            var selectors string
-            var containers string
-            var images string
-            rc, err := client.ReplicationControllers(api.NamespaceDefault).Get(name)
+	for service, selector := range selectors {
+		if c.BoolT("svc") {
+			results[service] = deleteServices(client, selector)
+		} else if c.BoolT("rc") {
+			results[service] = deleteReplicationControllers(client, selector)
+		}
+	}
 
-            /* Should grab controller, container, image, selector, replicas */
+	// The orphans are not a service of the project, their failure is reported
+	// on its own.
+	if err := results[""]; err != nil {
+		return err
+	}
+	delete(results, "")
+	return project.NewActionError(results)
+}
 
-            if err != nil {
-                logrus.Debugf("Cannot find rc for: ", string(name))
-            } else {
+func deleteServices(client *client.Client, selector labels.Selector) error {
+	services, err := client.Services(api.NamespaceDefault).List(selector)
+	if err != nil {
+		return fmt.Errorf("Unable to list services matching %s: %v", selector, err)
+	}
+	for _, service := range services.Items {
+		if err := client.Services(api.NamespaceDefault).Delete(service.Name); err != nil {
+			return fmt.Errorf("Unable to delete service %s: %v", service.Name, err)
+		}
+	}
+	return nil
+}
 
-                for k,v := range rc.Spec.Selector {
-                    selectors += selectors + k + "=" + v + ","
-                }
+func deleteReplicationControllers(client *client.Client, selector labels.Selector) error {
+	rcs, err := client.ReplicationControllers(api.NamespaceDefault).List(selector)
+	if err != nil {
+		return fmt.Errorf("Unable to list replication controllers matching %s: %v", selector, err)
+	}
+	for _, rc := range rcs.Items {
+		if err := client.ReplicationControllers(api.NamespaceDefault).Delete(rc.Name); err != nil {
+			return fmt.Errorf("Unable to delete replication controller %s: %v", rc.Name, err)
+		}
+	}
+	return nil
+}
 
-                for i := range rc.Spec.Template.Spec.Containers {
-                    c := rc.Spec.Template.Spec.Containers[i]
-                    containers += containers + c.Name + ","
-                    images += images + c.Image + ","
-                }
-                selectors = strings.TrimSuffix(selectors, ",")
-                containers = strings.TrimSuffix(containers, ",")
-                images = strings.TrimSuffix(images, ",")
+// ProjectKuber converts the services of the project with the kubernetes
+// transformer, writes their objects to the current directory and, unless they
+// are only generated as deployments or charts, creates them on the cluster. A
+// service failing to be created does not stop the other ones.
+func ProjectKuber(p *project.Project, c *cli.Context) error {
+	createInstance := !c.BoolT("deployment") && !c.BoolT("chart")
 
-                fmt.Printf("%-15s%-15s%-30s%-10d%-20s\n", rc.ObjectMeta.Name, containers,
-                    images, rc.Spec.Replicas, selectors)
-            }
-        }
+	options := convertOptions(c)
+	if c.BoolT("yaml") {
+		options.Format = "yaml"
 	}
 
-}
+	artifacts, err := transformer.Convert(p, "kubernetes", options)
+	if err != nil {
+		return err
+	}
 
-func ProjectKuberDelete(p *project.Project, c *cli.Context) {
-    server := getK8sServer("")
-    version := "v1"
-    client := client.NewOrDie(&client.Config{Host: server, Version: version})
+	if err := transformer.Write(".", artifacts); err != nil {
+		return fmt.Errorf("Failed to write the objects of project %s: %v", p.Name, err)
+	}
 
-    for name := range p.Configs {
-		if len(c.String("name")) > 0 && name != c.String("name") {
-			continue
+	results := map[string]error{}
+	for name := range p.Configs {
+		results[name] = nil
+	}
+
+	if createInstance {
+		client, err := newK8sClient()
+		if err != nil {
+			return err
 		}
 
-        if c.BoolT("svc") {
-            err := client.Services(api.NamespaceDefault).Delete(name)
-            if err != nil {
-                logrus.Fatalf("Unable to delete service %s: %s\n", name, err)
-            }
-        } else if c.BoolT("rc") {
-            err := client.ReplicationControllers(api.NamespaceDefault).Delete(name)
-            if err != nil {
-                logrus.Fatalf("Unable to delete replication controller %s: %s\n", name, err)
-            }
-        }
-    }
+		// The objects of the whole project, like the pull secret, are
+		// needed by the services.
+		for _, artifact := range artifacts {
+			if artifact.Service != "" {
+				continue
+			}
+			if err := createArtifact(client, artifact); err != nil {
+				return fmt.Errorf("Failed to create %s: %v", artifact.Name, err)
+			}
+		}
+
+		for _, artifact := range artifacts {
+			if artifact.Service == "" || results[artifact.Service] != nil {
+				continue
+			}
+			if err := createArtifact(client, artifact); err != nil {
+				results[artifact.Service] = fmt.Errorf("Failed to create %s: %v", artifact.Name, err)
+			}
+		}
+	}
+
+	if c.BoolT("chart") {
+		if err := generateHelm(p.File, artifacts); err != nil {
+			return fmt.Errorf("Failed to create Chart data: %v", err)
+		}
+	}
+
+	return project.NewActionError(results)
+}
+
+// convertOptions returns the options of the kubernetes transformer set by the
+// flags of the commands converting the project, shared with k8s convert so
+// that they compare or apply the objects it generates.
+func convertOptions(c *cli.Context) transformer.Options {
+	return transformer.Options{
+		Format:          "json",
+		Deployment:      c.BoolT("deployment"),
+		WaitForDeps:     c.Bool("wait-for-deps"),
+		NetworkPolicies: c.Bool("network-policies"),
+		PullSecrets:     c.Bool("pull-secrets"),
+		ConfigDir:       c.GlobalString("configdir"),
+	}
+}
+
+// artifactResource is the resource of a kind of object and the API version the
+// kubernetes transformer generates it for.
+type artifactResource struct {
+	Resource, APIVersion string
+}
+
+// artifactResources holds the resource of each kind of object generated by the
+// kubernetes transformer.
+var artifactResources = map[string]artifactResource{
+	"CronJob":                 {"cronjobs", "batch/v2alpha1"},
+	"Deployment":              {"deployments", "extensions/v1beta1"},
+	"HorizontalPodAutoscaler": {"horizontalpodautoscalers", "extensions/v1beta1"},
+	"Job":                     {"jobs", "batch/v1"},
+	"NetworkPolicy":           {"networkpolicies", "extensions/v1beta1"},
+	"ReplicationController":   {"replicationcontrollers", "v1"},
+	"Secret":                  {"secrets", "v1"},
+	"Service":                 {"services", "v1"},
+}
+
+// resourcePath returns the path of the resource of the objects of a kind and
+// API version, in the default namespace.
+func resourcePath(typeMeta unversioned.TypeMeta) ([]string, error) {
+	resource, ok := artifactResources[typeMeta.Kind]
+	if !ok {
+		return nil, fmt.Errorf("Unknown kind %q", typeMeta.Kind)
+	}
+
+	prefix := "/apis"
+	if typeMeta.APIVersion == "v1" {
+		prefix = "/api"
+	}
+	return []string{prefix, typeMeta.APIVersion, "namespaces", api.NamespaceDefault, resource.Resource}, nil
 }
 
-func ProjectKuberScale(p *project.Project, c *cli.Context) {
-    server := getK8sServer("")
-    version := "v1"
-    client := client.NewOrDie(&client.Config{Host: server, Version: version})
+// artifactObject is the object of an artifact, decoded from JSON.
+type artifactObject struct {
+	Ref controllerRef
+	// Path is the path of the resource of the object.
+	Path   []string
+	Data   []byte
+	Object map[string]interface{}
+}
+
+// decodeArtifact decodes the object of an artifact, generated as JSON or yaml.
+func decodeArtifact(artifact transformer.Artifact) (*artifactObject, error) {
+	data, err := yaml.YAMLToJSON(artifact.Data)
+	if err != nil {
+		return nil, err
+	}
 
-    if c.Int("scale") <= 0 {
-        logrus.Fatalf("Scale must be defined and a positive number")
-    }
+	typeMeta := unversioned.TypeMeta{}
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return nil, err
+	}
+	path, err := resourcePath(typeMeta)
+	if err != nil {
+		return nil, err
+	}
 
-    for name := range p.Configs {
-        if len(c.String("rc")) == 0 || c.String("rc") == name {
-            s, err := client.ExtensionsClient.Scales(api.NamespaceDefault).Get("ReplicationController", name)
-            if err != nil {
-                logrus.Fatalf("Error retrieving scaling data: %s\n", err)
-            }
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	metadata, _ := object["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+
+	return &artifactObject{
+		Ref:    controllerRef{typeMeta.Kind, name},
+		Path:   path,
+		Data:   data,
+		Object: object,
+	}, nil
+}
 
-            s.Spec.Replicas = c.Int("scale")
+// getObject returns the live version of an object of the resource at path, as
+// decoded from JSON, or nil if it does not exist.
+func getObject(client *client.Client, path []string, name string) (map[string]interface{}, error) {
+	data, err := client.Get().AbsPath(objectPath(path, name)...).Do().Raw()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
 
-            s, err = client.ExtensionsClient.Scales(api.NamespaceDefault).Update("ReplicationController", s)
-            if err != nil {
-                logrus.Fatalf("Error updating scaling data: %s\n", err)
-            }
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
 
-            fmt.Printf("Scaling %s to: %d\n", name, s.Spec.Replicas)
-        }
-    }
+// objectPath returns the path of an object of the resource at path.
+func objectPath(path []string, name string) []string {
+	return append(append([]string{}, path...), name)
 }
 
-func ProjectKuber(p *project.Project, c *cli.Context) {
-    createInstance := true
-    generateYaml := false
-    composeFile := c.String("file")
-
-    p = project.NewProject(&project.Context{
-        ProjectName: "kube",
-        ComposeFile: composeFile,
-    })
-
-    if err := p.Parse(); err != nil {
-        logrus.Fatalf("Failed to parse the compose project from %s: %v", composeFile, err)
-    }
-
-    server := getK8sServer("")
-
-    if c.BoolT("deployment") || c.BoolT("chart") {
-        createInstance = false
-    }
-
-    if c.BoolT("yaml") {
-      generateYaml = true
-    }
-
-    var mServices map[string]api.Service = make(map[string]api.Service)
-    var serviceLinks []string
-
-    version := "v1"
-    // create new client
-    client := client.NewOrDie(&client.Config{Host: server, Version: version})
-
-    for name, service := range p.Configs {
-        rc := &api.ReplicationController{
-            TypeMeta: unversioned.TypeMeta{
-                Kind:       "ReplicationController",
-                APIVersion: "v1",
-            },
-            ObjectMeta: api.ObjectMeta{
-                Name:   name,
-                Labels: map[string]string{"service": name},
-            },
-            Spec: api.ReplicationControllerSpec{
-                Replicas: 1,
-                Selector: map[string]string{"service": name},
-                Template: &api.PodTemplateSpec{
-                    ObjectMeta: api.ObjectMeta{
-                        Labels: map[string]string{"service": name},
-                    },
-                    Spec: api.PodSpec{
-                        Containers: []api.Container{
-                            {
-                                Name:  name,
-                                Image: service.Image,
-                            },
-                        },
-                    },
-                },
-            },
-        }
-        sc := &api.Service{
-            TypeMeta: unversioned.TypeMeta{
-                Kind:       "Service",
-                APIVersion: "v1",
-            },
-            ObjectMeta: api.ObjectMeta{
-                Name:   name,
-                Labels: map[string]string{"service": name},
-            },
-            Spec: api.ServiceSpec{
-                Selector: map[string]string{"service": name},
-            },
-        }
-
-        dc := &extensions.Deployment {
-            TypeMeta: unversioned.TypeMeta {
-                Kind:       "Deployment",
-                APIVersion: "extensions/v1beta1",
-            },
-            ObjectMeta: api.ObjectMeta{
-                Name: name,
-                Labels: map[string]string{"service": name},
-            },
-            Spec: extensions.DeploymentSpec {
-                Replicas: 1,
-                Selector: map[string]string{"service": name},
-                UniqueLabelKey: p.Name,
-                Template: &api.PodTemplateSpec {
-                    ObjectMeta: api.ObjectMeta {
-                        Labels: map[string]string {"service": name},
-                    },
-                    Spec: api.PodSpec {
-                        Containers: []api.Container {
-                            {
-                                Name:  name,
-                                Image: service.Image,
-                            },
-                        },
-                    },
-                },
-            },
-        }
-
-        // Configure the environment variables.
-        var envs []api.EnvVar
-        for _, env := range service.Environment.Slice() {
-            var character string = "="
-            if strings.Contains(env, character) {
-                value := env[strings.Index(env, character) + 1: len(env)]
-                name :=  env[0:strings.Index(env, character)]
-                name = strings.TrimSpace(name)
-                value = strings.TrimSpace(value)
-                envs = append(envs, api.EnvVar{
-                                               Name: name,
-                                               Value: value,
-                                              })
-            } else {
-                character = ":"
-                if strings.Contains(env, character) {
-                  var charQuote string = "'"
-                  value := env[strings.Index(env, character) + 1: len(env)]
-                  name := env[0:strings.Index(env, character)]
-                  name = strings.TrimSpace(name)
-                  value = strings.TrimSpace(value)
-                  if strings.Contains(value, charQuote) {
-                    value = strings.Trim(value, "'")
-                  }
-                  envs = append(envs, api.EnvVar{
-                                                 Name: name,
-                                                 Value: value,
-                                                })
-                } else {
-                  logrus.Fatalf("Invalid container env %s for service %s", env, name)
-                }
-            }
-        }
-
-        rc.Spec.Template.Spec.Containers[0].Env = envs
-
-        // Configure the container ports.
-        var ports []api.ContainerPort
-        for _, port := range service.Ports {
-            var character string = ":"
-            if strings.Contains(port, character) {
-                //portNumber := port[0:strings.Index(port, character)]
-                targetPortNumber := port[strings.Index(port, character) + 1: len(port)]
-                targetPortNumber = strings.TrimSpace(targetPortNumber)
-                targetPortNumberInt, err := strconv.Atoi(targetPortNumber)
-                if err != nil {
-                    logrus.Fatalf("Invalid container port %s for service %s", port, name)
-                }
-                ports = append(ports, api.ContainerPort{ContainerPort: targetPortNumberInt})
-            } else {
-                portNumber, err := strconv.Atoi(port)
-                if err != nil {
-                    logrus.Fatalf("Invalid container port %s for service %s", port, name)
-                }
-                ports = append(ports, api.ContainerPort{ContainerPort: portNumber})
-            }
-        }
-
-        rc.Spec.Template.Spec.Containers[0].Ports = ports
-
-        // Configure the service ports.
-        var servicePorts []api.ServicePort
-        for _, port := range service.Ports {
-            var character string = ":"
-            if strings.Contains(port, character) {
-                portNumber := port[0:strings.Index(port, character)]
-                portNumber = strings.TrimSpace(portNumber)
-                targetPortNumber := port[strings.Index(port, character) + 1: len(port)]
-                targetPortNumber = strings.TrimSpace(targetPortNumber)
-                portNumberInt, err := strconv.Atoi(portNumber)
-                if err != nil {
-                    logrus.Fatalf("Invalid container port %s for service %s", port, name)
-                }
-                targetPortNumberInt, err1 := strconv.Atoi(targetPortNumber)
-                if err1 != nil {
-                    logrus.Fatalf("Invalid container port %s for service %s", port, name)
-                }
-                var targetPort util.IntOrString
-                targetPort.StrVal = targetPortNumber
-                targetPort.IntVal = targetPortNumberInt
-                servicePorts = append(servicePorts, api.ServicePort{Port: portNumberInt, Name: portNumber, Protocol: "TCP", TargetPort: targetPort})
-            } else {
-                portNumber, err := strconv.Atoi(port)
-                if err != nil {
-                    logrus.Fatalf("Invalid container port %s for service %s", port, name)
-                }
-                var targetPort util.IntOrString
-                targetPort.StrVal = strconv.Itoa(portNumber)
-                targetPort.IntVal = portNumber
-                servicePorts = append(servicePorts, api.ServicePort{Port: portNumber, Name: strconv.Itoa(portNumber), Protocol: "TCP", TargetPort: targetPort})
-            }
-        }
-        sc.Spec.Ports = servicePorts
-
-        // Configure the container restart policy.
-        switch service.Restart {
-        case "", "always":
-            rc.Spec.Template.Spec.RestartPolicy = api.RestartPolicyAlways
-        case "no":
-            rc.Spec.Template.Spec.RestartPolicy = api.RestartPolicyNever
-        case "on-failure":
-            rc.Spec.Template.Spec.RestartPolicy = api.RestartPolicyOnFailure
-        default:
-            logrus.Fatalf("Unknown restart policy %s for service %s", service.Restart, name)
-        }
-
-        // Configure the container privileged mode
-        if service.Privileged == true {
-          securitycontexts := &api.SecurityContext{
-            Privileged: &service.Privileged,
-          }
-          rc.Spec.Template.Spec.Containers[0].SecurityContext = securitycontexts
-        }
-
-        // convert datarc to json / yaml
-        datarc, err := json.MarshalIndent(rc, "", "  ")
-        if generateYaml == true {
-          datarc, err = yaml.Marshal(rc)
-        }
-
-        if err != nil {
-            logrus.Fatalf("Failed to marshal the replication controller: %v", err)
-        }
-        logrus.Debugf("%s\n", datarc)
-
-        // convert datasc to json / yaml
-        datasc, er := json.MarshalIndent(sc, "", "  ")
-        if generateYaml == true {
-          datasc, er = yaml.Marshal(sc)
-        }
-
-        if er != nil {
-            logrus.Fatalf("Failed to marshal the service controller: %v", er)
-        }
-
-        logrus.Debugf("%s\n", datasc)
-
-        // convert datadc to json / yaml
-        datadc, err := json.MarshalIndent(dc, "", "  ")
-        if generateYaml == true {
-          datadc, err = yaml.Marshal(dc)
-        }
-
-        if err != nil {
-            logrus.Fatalf("Failed to marshal the deployment container: %v", err)
-        }
-
-        logrus.Debugf("%s\n", datadc)
-
-        mServices[name] = *sc
-
-        if len(service.Links.Slice()) > 0 {
-            for i := 0; i < len(service.Links.Slice()); i++ {
-                var data string = service.Links.Slice()[i]
-                if len(serviceLinks) == 0 {
-                    serviceLinks = append(serviceLinks, data)
-                } else {
-                    for _, v := range serviceLinks {
-                        if v != data {
-                            serviceLinks = append(serviceLinks, data)
-                        }
-                    }
-                }
-            }
-
-        }
-
-        // call create RC api
-        if createInstance == true {
-            rcCreated, err := client.ReplicationControllers(api.NamespaceDefault).Create(rc)
-            if err != nil {
-                fmt.Println(err)
-            }
-            logrus.Debugf("%s\n", rcCreated)
-        }
-
-        fileRC := fmt.Sprintf("%s-rc.json", name)
-        if generateYaml == true {
-          fileRC = fmt.Sprintf("%s-rc.yaml", name)
-        }
-        if err := ioutil.WriteFile(fileRC, []byte(datarc), 0644); err != nil {
-            logrus.Fatalf("Failed to write replication controller: %v", err)
-        }
-
-        /* Create the deployment container */
-        if c.BoolT("deployment") {
-            fileDC := fmt.Sprintf("%s-deployment.json", name)
-            if generateYaml == true {
-              fileDC = fmt.Sprintf("%s-deployment.yaml", name)
-            }
-            if err := ioutil.WriteFile(fileDC, []byte(datadc), 0644); err != nil {
-                logrus.Fatalf("Failed to write deployment container: %v", err)
-            }
-        }
-
-        for k, v := range mServices {
-            for i :=0; i < len(serviceLinks); i++ {
-                //if serviceLinks[i] == k {
-                    // call create SVC api
-                    if createInstance == true {
-                        scCreated, err := client.Services(api.NamespaceDefault).Create(&v)
-                        if err != nil {
-                            fmt.Println(err)
-                        }
-                        logrus.Debugf("%s\n", scCreated)
-                    }
-
-                    datasvc, er := json.MarshalIndent(v, "", "  ")
-                    if er != nil {
-                        logrus.Fatalf("Failed to marshal the service controller: %v", er)
-                    }
-
-                    fileSVC := fmt.Sprintf("%s-svc.json", k)
-                    if generateYaml == true {
-                      fileSVC = fmt.Sprintf("%s-svc.yaml", k)
-                    }
-
-                    if err := ioutil.WriteFile(fileSVC, []byte(datasvc), 0644); err != nil {
-                        logrus.Fatalf("Failed to write service controller: %v", err)
-                    }
-                //}
-            }
-        }
-    }
-
-    /* Need to iterate through one more time to ensure we capture all service/rc */
-    for name := range p.Configs {
-        if c.BoolT("chart") {
-            err := generateHelm(composeFile, name)
-            if err != nil {
-                logrus.Fatalf("Failed to create Chart data: %s\n", err)
-            }
-        }
-    }
+// createArtifact creates the object of an artifact on the cluster. Several
+// kinds, like jobs and network policies, are not part of the vendored client,
+// so every object is posted as raw JSON to the path of its API version. An
+// existing pull secret is updated, since the credentials may have changed.
+func createArtifact(client *client.Client, artifact transformer.Artifact) error {
+	object, err := decodeArtifact(artifact)
+	if err != nil {
+		return err
+	}
+
+	err = client.Post().AbsPath(object.Path...).Body(object.Data).Do().Error()
+	if !errors.IsAlreadyExists(err) || object.Ref.Kind != "Secret" {
+		return err
+	}
+
+	secret := &api.Secret{}
+	if err := json.Unmarshal(object.Data, secret); err != nil {
+		return err
+	}
+	secrets := client.Secrets(api.NamespaceDefault)
+	existing, err := secrets.Get(secret.Name)
+	if err != nil {
+		return err
+	}
+	secret.ResourceVersion = existing.ResourceVersion
+	_, err = secrets.Update(secret)
+	return err
 }
@@ -0,0 +1,741 @@
+package app
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/cli"
+	cliApp "github.com/docker/libcompose/cli/app"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/kubernetes/fake"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+// The commands are run end to end against a fake API server, the client of
+// the commands being replaced by one of the server.
+
+func newFakeServer(t *testing.T) (*fake.Server, func()) {
+	s := fake.NewServer()
+	newClient := NewClient
+	NewClient = func() (*client.Client, error) {
+		return s.Client(), nil
+	}
+	return s, func() {
+		NewClient = newClient
+		s.Close()
+	}
+}
+
+// newTestContext returns the context of a command with the specified flags,
+// parsed from args.
+func newTestContext(t *testing.T, args []string, flags ...cli.Flag) *cli.Context {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(set)
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(nil, set, flag.NewFlagSet("global", flag.ContinueOnError))
+}
+
+// captureStdout returns what f writes to the standard output.
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := make(chan string)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		output <- string(data)
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+	f()
+
+	w.Close()
+	return <-output
+}
+
+// createServices creates the replication controllers and services of the
+// services of the project on the server, as up does.
+func createServices(t *testing.T, s *fake.Server, p *project.Project) {
+	for _, name := range []string{"redis", "web"} {
+		objects, err := kubernetes.ConvertToAPI(p.Name, name, p.Configs[name])
+		assert.Nil(t, err)
+		assert.Nil(t, s.Create("replicationcontrollers", objects.ReplicationController))
+		assert.Nil(t, s.Create("services", objects.Service))
+	}
+}
+
+func inTempDir(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "kompose")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestProjectKuberConvertCreate(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+	defer inTempDir(t)()
+
+	p := newTestProject()
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis"})
+	p.Configs["redis"].Ports = []string{"6379"}
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{kubernetes.AutoscaleMaxLabel: "3"})
+	p.Configs["migrate"] = &project.ServiceConfig{
+		Image:  "web",
+		Labels: project.NewSliceorMap(map[string]string{kubernetes.JobLabel: "true"}),
+	}
+
+	assert.Nil(t, ProjectKuber(p, newTestContext(t, []string{"--yaml", "--network-policies"},
+		cli.BoolFlag{Name: "deployment,d"},
+		cli.BoolFlag{Name: "chart,c"},
+		cli.BoolFlag{Name: "yaml, y"},
+		cli.BoolFlag{Name: "pull-secrets"},
+		cli.BoolFlag{Name: "wait-for-deps"},
+		cli.BoolFlag{Name: "network-policies"},
+	)))
+
+	assert.Equal(t, []string{"redis", "web"}, s.Names("replicationcontrollers"))
+	// Only redis publishes a port, so web has no service.
+	assert.Equal(t, []string{"redis"}, s.Names("services"))
+	assert.Equal(t, []string{"migrate", "redis", "web"}, s.Names("networkpolicies"))
+	assert.Equal(t, []string{"web"}, s.Names("horizontalpodautoscalers"))
+	assert.Equal(t, []string{"migrate"}, s.Names("jobs"))
+
+	// The files are the ones of kompose convert --to kubernetes.
+	files, err := filepath.Glob("*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"migrate-job.yaml", "migrate-networkpolicy.yaml",
+		"redis-networkpolicy.yaml", "redis-rc.yaml", "redis-svc.yaml",
+		"web-hpa.yaml", "web-networkpolicy.yaml", "web-rc.yaml",
+	}, files)
+}
+
+func TestProjectKuberConvertDeploymentOnly(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+	defer inTempDir(t)()
+
+	assert.Nil(t, ProjectKuber(newTestProject(), newTestContext(t, []string{"--deployment", "--yaml"},
+		cli.BoolFlag{Name: "deployment,d"},
+		cli.BoolFlag{Name: "chart,c"},
+		cli.BoolFlag{Name: "yaml, y"},
+	)))
+
+	// Deployments are only written to files.
+	assert.Empty(t, s.Requests())
+	files, err := filepath.Glob("*-deployment.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"redis-deployment.yaml", "web-deployment.yaml"}, files)
+}
+
+func TestProjectKuberConvertChart(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+	defer inTempDir(t)()
+
+	p := newTestProject()
+	p.File = "docker-compose.yml"
+	p.Configs["web"].Ports = []string{"80"}
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{kubernetes.AutoscaleMaxLabel: "3"})
+	p.Configs["migrate"] = &project.ServiceConfig{
+		Image:  "web",
+		Labels: project.NewSliceorMap(map[string]string{kubernetes.JobLabel: "true"}),
+	}
+
+	assert.Nil(t, ProjectKuber(p, newTestContext(t, []string{"--chart", "--deployment"},
+		cli.BoolFlag{Name: "deployment,d"},
+		cli.BoolFlag{Name: "chart,c"},
+		cli.BoolFlag{Name: "yaml, y"},
+		cli.BoolFlag{Name: "network-policies"},
+	)))
+
+	assert.Empty(t, s.Requests())
+	files, err := filepath.Glob(filepath.Join("docker-compose", "manifests", "*"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"docker-compose/manifests/migrate-job.json",
+		"docker-compose/manifests/redis-deployment.json",
+		"docker-compose/manifests/web-deployment.json",
+		"docker-compose/manifests/web-hpa.json",
+		"docker-compose/manifests/web-svc.json",
+	}, files)
+	_, err = os.Stat(filepath.Join("docker-compose", "Chart.yaml"))
+	assert.Nil(t, err)
+}
+
+func TestProjectKuberPS(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+
+	output := captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberPS(p, newTestContext(t, []string{"--format", "{{.Service}} {{.Controller}} {{.Ready}}/{{.Desired}}"},
+			cli.StringFlag{Name: "output,o"},
+			cli.StringFlag{Name: "format"},
+		)))
+	})
+	assert.Equal(t, "redis rc/redis 1/1\nweb rc/web 1/1\n", output)
+}
+
+func TestProjectKuberPSDeployment(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	objects, err := kubernetes.ConvertToAPI(p.Name, "web", p.Configs["web"])
+	assert.Nil(t, err)
+	objects.Deployment.Spec.Replicas = 2
+	assert.Nil(t, s.Create("deployments", objects.Deployment))
+	objects, err = kubernetes.ConvertToAPI(p.Name, "redis", p.Configs["redis"])
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("replicationcontrollers", objects.ReplicationController))
+
+	// The replication controller of the deployment is not counted again.
+	assert.Len(t, s.Names("replicationcontrollers"), 2)
+	output := captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberPS(p, newTestContext(t, []string{"--format", "{{.Service}} {{.Controller}} {{.Ready}}/{{.Current}}/{{.Desired}}"},
+			cli.StringFlag{Name: "output,o"},
+			cli.StringFlag{Name: "format"},
+		)))
+	})
+	assert.Equal(t, "redis rc/redis 1/1/1\nweb deployment/web 2/2/2\n", output)
+}
+
+func TestProjectKuberDelete(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+
+	flags := []cli.Flag{
+		cli.BoolFlag{Name: "replicationcontroller,rc"},
+		cli.BoolFlag{Name: "service,svc"},
+		cli.StringFlag{Name: "name"},
+		cli.BoolFlag{Name: "remove-orphans"},
+	}
+
+	assert.Nil(t, ProjectKuberDelete(p, newTestContext(t, []string{"--svc", "--name", "web"}, flags...)))
+	assert.Equal(t, []string{"redis"}, s.Names("services"))
+	assert.Equal(t, []string{"redis", "web"}, s.Names("replicationcontrollers"))
+
+	assert.Nil(t, ProjectKuberDelete(p, newTestContext(t, []string{"--rc"}, flags...)))
+	assert.Empty(t, s.Names("replicationcontrollers"))
+}
+
+func TestProjectKuberDown(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+	assert.Nil(t, s.Create("secrets", &api.Secret{ObjectMeta: api.ObjectMeta{Name: "demo-registry", Labels: kubernetes.SharedLabels("demo", "demo-registry")}}))
+	// The cron job is running a job, named after the time it started.
+	p.Configs["migrate"] = &project.ServiceConfig{Image: "migrate", Labels: project.NewSliceorMap(map[string]string{"kompose.job": "true"})}
+	p.Configs["report"] = &project.ServiceConfig{Image: "report", Labels: project.NewSliceorMap(map[string]string{"kompose.schedule": "@daily"})}
+	job, err := kubernetes.ParseJob("migrate", p.Configs["migrate"])
+	assert.Nil(t, err)
+	migrate, err := kubernetes.ConvertToJob(p.Name, "migrate", p.Configs["migrate"], job)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("jobs", migrate))
+	job, err = kubernetes.ParseJob("report", p.Configs["report"])
+	assert.Nil(t, err)
+	report, err := kubernetes.ConvertToCronJob(p.Name, "report", p.Configs["report"], job)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("cronjobs", report))
+	run, err := kubernetes.ConvertToJob(p.Name, "report", p.Configs["report"], job)
+	assert.Nil(t, err)
+	run.Name = "report-1476230400"
+	assert.Nil(t, s.Create("jobs", run))
+	assert.Len(t, s.Names("pods"), 4)
+	// Objects of other projects are kept.
+	assert.Nil(t, s.Create("services", &api.Service{ObjectMeta: api.ObjectMeta{Name: "other"}}))
+	assert.Nil(t, s.Create("secrets", &api.Secret{ObjectMeta: api.ObjectMeta{Name: "other-registry", Labels: kubernetes.SharedLabels("other", "other-registry")}}))
+
+	assert.Nil(t, ProjectKuberDown(p, newTestContext(t, []string{"--yes"},
+		cli.BoolFlag{Name: "yes,y"},
+		cli.BoolFlag{Name: "volumes,v"},
+		cli.IntFlag{Name: "timeout,t", Value: 5},
+		cli.IntFlag{Name: "grace-period"},
+	)))
+
+	assert.Empty(t, s.Names("replicationcontrollers"))
+	assert.Empty(t, s.Names("jobs"))
+	assert.Empty(t, s.Names("cronjobs"))
+	assert.Empty(t, s.Names("pods"))
+	assert.Equal(t, []string{"other"}, s.Names("services"))
+	assert.Equal(t, []string{"other-registry"}, s.Names("secrets"))
+}
+
+func TestProjectKuberScale(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+
+	assert.Nil(t, ProjectKuberScale(p, newTestContext(t, []string{"--wait", "--timeout", "5", "web=3", "redis=0"},
+		cli.BoolFlag{Name: "wait,w"},
+		cli.IntFlag{Name: "timeout,t", Value: 300},
+	)))
+
+	rc := api.ReplicationController{}
+	assert.Nil(t, s.Get("replicationcontrollers", "web", &rc))
+	assert.Equal(t, 3, rc.Spec.Replicas)
+	assert.Nil(t, s.Get("replicationcontrollers", "redis", &rc))
+	assert.Equal(t, 0, rc.Spec.Replicas)
+	assert.Len(t, s.Names("pods"), 3)
+}
+
+func TestProjectKuberScaleDeployment(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	p.Configs["migrate"] = &project.ServiceConfig{Image: "migrate", Labels: project.NewSliceorMap(map[string]string{"kompose.job": "true"})}
+	objects, err := kubernetes.ConvertToAPI(p.Name, "web", p.Configs["web"])
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("deployments", objects.Deployment))
+	objects, err = kubernetes.ConvertToAPI(p.Name, "migrate", p.Configs["migrate"])
+	assert.Nil(t, err)
+	job, err := kubernetes.ConvertToJob(p.Name, "migrate", p.Configs["migrate"], objects.Job)
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("jobs", job))
+
+	err = ProjectKuberScale(p, newTestContext(t, []string{"--wait", "--timeout", "5", "web=3", "migrate=2"},
+		cli.BoolFlag{Name: "wait,w"},
+		cli.IntFlag{Name: "timeout,t", Value: 300},
+	))
+	if assert.IsType(t, &project.ActionError{}, err) {
+		actionError := err.(*project.ActionError)
+		assert.Equal(t, []string{"web"}, actionError.Succeeded)
+		assert.Contains(t, actionError.Failed[0].Error(), "cannot be scaled")
+	}
+
+	// Only the deployment is scaled, its replication controller follows it.
+	dc := extensions.Deployment{}
+	assert.Nil(t, s.Get("deployments", "web", &dc))
+	assert.Equal(t, 3, dc.Spec.Replicas)
+	rcs := s.Names("replicationcontrollers")
+	if assert.Len(t, rcs, 1) {
+		rc := api.ReplicationController{}
+		assert.Nil(t, s.Get("replicationcontrollers", rcs[0], &rc))
+		assert.Equal(t, 3, rc.Spec.Replicas)
+	}
+	// The job keeps running its own pod.
+	assert.Len(t, s.Names("pods"), 4)
+}
+
+func TestProjectKuberScaleFailures(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	objects, err := kubernetes.ConvertToAPI(p.Name, "web", p.Configs["web"])
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("replicationcontrollers", objects.ReplicationController))
+
+	flags := []cli.Flag{
+		cli.BoolFlag{Name: "wait,w"},
+		cli.IntFlag{Name: "timeout,t", Value: 300},
+	}
+
+	err = ProjectKuberScale(p, newTestContext(t, []string{"web=2", "db=1"}, flags...))
+	assert.True(t, project.IsServiceNotFound(err), "%v", err)
+	assert.Equal(t, cliApp.ExitServiceNotFound, cliApp.ExitCode(err))
+	// The arguments are checked before scaling any service.
+	rc := api.ReplicationController{}
+	assert.Nil(t, s.Get("replicationcontrollers", "web", &rc))
+	assert.Equal(t, 1, rc.Spec.Replicas)
+
+	// Redis has no controller, web is scaled anyway.
+	err = ProjectKuberScale(p, newTestContext(t, []string{"redis=2", "web=2"}, flags...))
+	if assert.IsType(t, &project.ActionError{}, err) {
+		actionError := err.(*project.ActionError)
+		assert.Equal(t, []string{"web"}, actionError.Succeeded)
+		assert.Len(t, actionError.Failed, 1)
+		assert.Equal(t, "redis", actionError.Failed[0].Service)
+		assert.Equal(t, "failed", actionError.Summary()[1][1].Value)
+	}
+	assert.Equal(t, cliApp.ExitFailure, cliApp.ExitCode(err))
+	assert.Nil(t, s.Get("replicationcontrollers", "web", &rc))
+	assert.Equal(t, 2, rc.Spec.Replicas)
+}
+
+func TestProjectKuberConvertInvalidService(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+	defer inTempDir(t)()
+
+	p := newTestProject()
+	p.Configs["redis"].Restart = "sometimes"
+
+	err := ProjectKuber(p, newTestContext(t, nil,
+		cli.BoolFlag{Name: "deployment,d"},
+		cli.BoolFlag{Name: "chart,c"},
+		cli.BoolFlag{Name: "yaml, y"},
+	))
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "redis.restart", err.(*project.ValidationError).Path())
+	}
+	assert.Equal(t, cliApp.ExitValidation, cliApp.ExitCode(err))
+
+	// The project is converted as a whole, nothing is written or created.
+	assert.Empty(t, s.Requests())
+	files, _ := filepath.Glob("*")
+	assert.Empty(t, files)
+}
+
+func TestProjectKuberConvertCreateFailure(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+	defer inTempDir(t)()
+
+	p := newTestProject()
+	objects, err := kubernetes.ConvertToAPI(p.Name, "redis", p.Configs["redis"])
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("replicationcontrollers", objects.ReplicationController))
+
+	err = ProjectKuber(p, newTestContext(t, nil,
+		cli.BoolFlag{Name: "deployment,d"},
+		cli.BoolFlag{Name: "chart,c"},
+		cli.BoolFlag{Name: "yaml, y"},
+	))
+	if assert.IsType(t, &project.ActionError{}, err) {
+		actionError := err.(*project.ActionError)
+		assert.Equal(t, []string{"web"}, actionError.Succeeded)
+		assert.Equal(t, "redis", actionError.Failed[0].Service)
+	}
+	assert.Equal(t, []string{"redis", "web"}, s.Names("replicationcontrollers"))
+}
+
+func TestResourcePath(t *testing.T) {
+	path, err := resourcePath(unversioned.TypeMeta{Kind: "NetworkPolicy", APIVersion: "extensions/v1beta1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/apis", "extensions/v1beta1", "namespaces", "default", "networkpolicies"}, path)
+
+	path, err = resourcePath(unversioned.TypeMeta{Kind: "Service", APIVersion: "v1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/api", "v1", "namespaces", "default", "services"}, path)
+
+	_, err = resourcePath(unversioned.TypeMeta{Kind: "Ingress", APIVersion: "extensions/v1beta1"})
+	assert.NotNil(t, err)
+}
+
+func TestProjectKuberLogs(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+	for _, pod := range s.Names("pods") {
+		s.SetLog(pod, "started "+pod+"\n")
+	}
+	pods := s.Names("pods")
+
+	output := captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberLogs(p, newTestContext(t, []string{"web"},
+			cli.BoolFlag{Name: "follow,f"},
+			cli.StringFlag{Name: "tail", Value: "all"},
+			cli.StringFlag{Name: "since"},
+		)))
+	})
+
+	for _, pod := range pods {
+		if strings.HasPrefix(pod, "web-") {
+			assert.Contains(t, output, "started "+pod)
+		} else {
+			assert.NotContains(t, output, "started "+pod)
+		}
+	}
+}
+
+func TestProjectKuberStatus(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+
+	events := make(chan project.Event, 10)
+	p.AddListener(events)
+
+	assert.Nil(t, ProjectKuberStatus(p, newTestContext(t, []string{"web"},
+		cli.IntFlag{Name: "timeout,t", Value: 5},
+	)))
+
+	// The pods of the fake server are ready right away.
+	event := <-events
+	assert.Equal(t, project.EventServiceRollout, event.EventType)
+	assert.Equal(t, "web", event.ServiceName)
+	assert.Equal(t, "1", event.Data["available"])
+}
+
+func TestProjectKuberStatusJob(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	createServices(t, s, p)
+	p.Configs["migrate"] = &project.ServiceConfig{Image: "migrate", Labels: project.NewSliceorMap(map[string]string{kubernetes.JobLabel: "true"})}
+
+	events := make(chan project.Event, 10)
+	p.AddListener(events)
+
+	// The job has no controller to roll out, the other services are followed.
+	assert.Nil(t, ProjectKuberStatus(p, newTestContext(t, nil,
+		cli.IntFlag{Name: "timeout,t", Value: 5},
+	)))
+
+	services := []string{}
+	for len(services) < 2 {
+		event := <-events
+		assert.Equal(t, project.EventServiceRollout, event.EventType)
+		services = append(services, event.ServiceName)
+	}
+	assert.Equal(t, []string{"redis", "web"}, services)
+}
+
+func TestProjectKuberRollback(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	objects, err := kubernetes.ConvertToAPI(p.Name, "web", p.Configs["web"])
+	assert.Nil(t, err)
+	assert.Nil(t, s.Create("deployments", objects.Deployment))
+
+	deployments := s.Client().Extensions().Deployments(api.NamespaceDefault)
+	p.Configs["web"].Image = "nginx:1.9"
+	update, err := kubernetes.ConvertToAPI(p.Name, "web", p.Configs["web"])
+	assert.Nil(t, err)
+	dc, err := deployments.Get("web")
+	assert.Nil(t, err)
+	dc.Spec.Template = update.Deployment.Spec.Template
+	_, err = deployments.Update(dc)
+	assert.Nil(t, err)
+
+	output := captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberHistory(p, newTestContext(t, []string{"web"})))
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	assert.Len(t, lines, 3)
+
+	output = captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberRollback(p, newTestContext(t, []string{"web"},
+			cli.IntFlag{Name: "to-revision"},
+		)))
+	})
+	assert.Contains(t, output, "web")
+
+	dc, err = deployments.Get("web")
+	assert.Nil(t, err)
+	assert.Equal(t, "nginx", dc.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "3", dc.Annotations[kubernetes.RevisionAnnotation])
+}
+
+func TestProjectKuberUp(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p, err := kubernetes.NewProject(&kubernetes.Context{
+		Context: project.Context{
+			ComposeBytes: []byte("web:\n  image: nginx:1.9\n  ports:\n  - \"80\"\nredis:\n  image: redis:3\n"),
+			ProjectName:  "demo",
+		},
+		Client: s.Client(),
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, ProjectKuberUp(p, newTestContext(t, nil, cli.BoolFlag{Name: "watch,w"})))
+	assert.Equal(t, []string{"redis", "web"}, s.Names("replicationcontrollers"))
+	assert.Equal(t, []string{"web"}, s.Names("services"))
+	assert.Len(t, s.Names("pods"), 2)
+}
+
+func TestProjectKuberDiff(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	p.Configs["web"].Image = "nginx:1.9"
+	p.Configs["web"].Ports = []string{"80"}
+	p.Configs["redis"].Image = "redis:3"
+	p.Configs["redis"].Ports = []string{"6379"}
+	createServices(t, s, p)
+
+	flags := []cli.Flag{cli.BoolFlag{Name: "deployment,d"}}
+	output := captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberDiff(p, newTestContext(t, nil, flags...)))
+	})
+	assert.Empty(t, output)
+
+	p.Configs["web"].Image = "nginx:1.10"
+	var err error
+	output = captureStdout(t, func() {
+		err = ProjectKuberDiff(p, newTestContext(t, nil, flags...))
+	})
+	assert.Equal(t, diffExitDrift, cliApp.ExitCode(err))
+	assert.Contains(t, output, "+++ generated/replicationcontroller/web")
+	assert.Contains(t, output, "+      - image: nginx:1.10")
+}
+
+func TestProjectKuberHistory(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	for _, name := range []string{"redis", "web"} {
+		objects, err := kubernetes.ConvertToAPI(p.Name, name, p.Configs[name])
+		assert.Nil(t, err)
+		assert.Nil(t, s.Create("deployments", objects.Deployment))
+	}
+
+	output := captureStdout(t, func() {
+		assert.Nil(t, ProjectKuberHistory(p, newTestContext(t, nil)))
+	})
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[1], "redis "), lines[1])
+		assert.True(t, strings.HasPrefix(lines[2], "web "), lines[2])
+	}
+
+	err := ProjectKuberHistory(p, newTestContext(t, []string{"db"}))
+	assert.True(t, project.IsServiceNotFound(err), "%v", err)
+}
+
+func TestProjectKuberConfig(t *testing.T) {
+	defer inTempDir(t)()
+
+	assert.Nil(t, ProjectKuberConfig(newTestProject(), newTestContext(t, []string{"--host", "10.0.0.1:8443"},
+		cli.StringFlag{Name: "host"},
+	)))
+
+	data, err := ioutil.ReadFile(".kuberconfig")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.0.0.1:8443", string(data))
+	assert.Equal(t, "10.0.0.1:8443", getK8sServer(""))
+}
+
+func TestCollectDiffPairs(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	// The fake server does not default the objects, the images are tagged so
+	// that the pull policy defaulted by the client is the one of a server.
+	p := newTestProject()
+	p.Configs["web"].Image = "nginx:1.9"
+	p.Configs["web"].Ports = []string{"80"}
+	p.Configs["redis"].Image = "redis:3"
+	p.Configs["redis"].Ports = []string{"6379"}
+	createServices(t, s, p)
+
+	pairs, err := collectDiffPairs(s.Client(), p, transformer.Options{})
+	assert.Nil(t, err)
+	assert.Len(t, pairs, 4)
+	for _, pair := range pairs {
+		diff, err := diffObjects(pair)
+		assert.Nil(t, err)
+		assert.Empty(t, diff, pair.Ref.String())
+	}
+
+	p.Configs["web"].Image = "nginx:1.10"
+	pairs, err = collectDiffPairs(s.Client(), p, transformer.Options{})
+	assert.Nil(t, err)
+	diff, err := diffObjects(pairs[2])
+	assert.Nil(t, err)
+	assert.Contains(t, diff, "+      - image: nginx:1.10")
+}
+
+func TestCollectDiffPairsConvertOptions(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+	defer inTempDir(t)()
+
+	p := newTestProject()
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis"})
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{kubernetes.AutoscaleMaxLabel: "3"})
+	p.Configs["redis"].Ports = []string{"6379"}
+	p.Configs["migrate"] = &project.ServiceConfig{
+		Image:   "web",
+		Command: project.NewCommand("./manage.py", "migrate"),
+		Labels:  project.NewSliceorMap(map[string]string{kubernetes.JobLabel: "true"}),
+	}
+
+	assert.Nil(t, ProjectKuber(p, newTestContext(t, []string{"--network-policies", "--wait-for-deps"},
+		cli.BoolFlag{Name: "deployment,d"},
+		cli.BoolFlag{Name: "chart,c"},
+		cli.BoolFlag{Name: "yaml, y"},
+		cli.BoolFlag{Name: "pull-secrets"},
+		cli.BoolFlag{Name: "wait-for-deps"},
+		cli.BoolFlag{Name: "network-policies"},
+	)))
+
+	// Every object generated with the options of the conversion matches.
+	pairs, err := collectDiffPairs(s.Client(), p, transformer.Options{WaitForDeps: true, NetworkPolicies: true})
+	assert.Nil(t, err)
+	refs := []string{}
+	for _, pair := range pairs {
+		refs = append(refs, pair.Ref.String())
+		diff, err := diffObjects(pair)
+		assert.Nil(t, err)
+		assert.Empty(t, diff, pair.Ref.String())
+	}
+	assert.Equal(t, []string{
+		"job/migrate", "networkpolicy/migrate",
+		"networkpolicy/redis", "replicationcontroller/redis", "service/redis",
+		"horizontalpodautoscaler/web", "networkpolicy/web", "replicationcontroller/web",
+	}, refs)
+
+	// Without them, the init containers and the network policies drift.
+	pairs, err = collectDiffPairs(s.Client(), p, transformer.Options{})
+	assert.Nil(t, err)
+	diffs := map[string]string{}
+	for _, pair := range pairs {
+		diff, err := diffObjects(pair)
+		assert.Nil(t, err)
+		if diff != "" {
+			diffs[pair.Ref.String()] = diff
+		}
+	}
+	assert.Len(t, diffs, 4)
+	assert.Contains(t, diffs["replicationcontroller/web"], "-        pod.alpha.kubernetes.io/init-containers:")
+	for _, name := range []string{"migrate", "redis", "web"} {
+		assert.Contains(t, diffs["networkpolicy/"+name], "--- live/networkpolicy/"+name)
+	}
+}
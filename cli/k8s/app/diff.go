@@ -0,0 +1,175 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/codegangsta/cli"
+	cliApp "github.com/docker/libcompose/cli/app"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+// Exit codes of the diff command, following diff(1) rather than the codes of
+// the other commands.
+const (
+	diffExitDrift = 1
+	diffExitError = 2
+)
+
+// objectPair holds the live and generated versions of an object, either of
+// which is nil when the object only exists on one side.
+type objectPair struct {
+	Ref       controllerRef
+	Live      interface{}
+	Generated interface{}
+}
+
+// ProjectKuberDiff prints a unified diff between the live objects of the
+// project and the objects k8s convert generates from the compose file, with
+// the same flags. It fails with an exit code of 1 when they differ and 2 on
+// errors.
+func ProjectKuberDiff(p *project.Project, c *cli.Context) error {
+	client, err := newK8sClient()
+	if err != nil {
+		return &cliApp.ExitError{Code: diffExitError, Err: err}
+	}
+
+	pairs, err := collectDiffPairs(client, p, convertOptions(c))
+	if err != nil {
+		return &cliApp.ExitError{Code: diffExitError, Err: fmt.Errorf("Failed to compare project %s with the cluster: %v", p.Name, err)}
+	}
+
+	drift := false
+	for _, pair := range pairs {
+		diff, err := diffObjects(pair)
+		if err != nil {
+			return &cliApp.ExitError{Code: diffExitError, Err: fmt.Errorf("Failed to compare %s: %v", pair.Ref, err)}
+		}
+		if diff != "" {
+			os.Stdout.WriteString(diff)
+			drift = true
+		}
+	}
+
+	if drift {
+		return &cliApp.ExitError{Code: diffExitDrift}
+	}
+	return nil
+}
+
+// collectDiffPairs converts the project with the kubernetes transformer and
+// fetches the live version of each object it generates, along with the other
+// objects of the project: the ones left behind by removed services or
+// generated with other options.
+func collectDiffPairs(client *client.Client, p *project.Project, options transformer.Options) ([]objectPair, error) {
+	artifacts, err := transformer.Convert(p, "kubernetes", options)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []objectPair{}
+	generated := map[controllerRef]bool{}
+	cronJobs := map[string]bool{}
+	for _, artifact := range artifacts {
+		object, err := decodeArtifact(artifact)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s: %v", artifact.Name, err)
+		}
+		generated[object.Ref] = true
+		if object.Ref.Kind == "CronJob" {
+			cronJobs[artifact.Service] = true
+		}
+
+		live, err := getObject(client, object.Path, object.Ref.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		pair := objectPair{Ref: object.Ref, Generated: kubernetes.NormalizeGenerated(object.Object)}
+		if live != nil {
+			pair.Live = kubernetes.NormalizeLive(live, pair.Generated.(map[string]interface{}))
+		}
+		pairs = append(pairs, pair)
+	}
+
+	kinds := []string{}
+	for kind := range artifactResources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	selector := kubernetes.ProjectSelector(p)
+	for _, kind := range kinds {
+		path, err := resourcePath(unversioned.TypeMeta{Kind: kind, APIVersion: artifactResources[kind].APIVersion})
+		if err != nil {
+			return nil, err
+		}
+		objects, err := listObjects(client.Get().AbsPath(path...), selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, meta := range objects {
+			ref := controllerRef{kind, meta.Name}
+			switch {
+			case generated[ref]:
+			// The deployments and cron jobs create their own controllers and jobs.
+			case kind == "ReplicationController" && meta.Labels[kubernetes.PodTemplateHashLabel] != "":
+			case kind == "Job" && cronJobs[meta.Labels[kubernetes.SERVICE.Str()]]:
+			default:
+				live, err := getObject(client, path, meta.Name)
+				if err != nil {
+					return nil, err
+				}
+				if live != nil {
+					pairs = append(pairs, objectPair{Ref: ref, Live: kubernetes.NormalizeLive(live, nil)})
+				}
+			}
+		}
+	}
+
+	return pairs, nil
+}
+
+// diffObjects returns the unified diff between the yaml representations of the
+// live and generated objects, or an empty string if they are identical.
+func diffObjects(pair objectPair) (string, error) {
+	live, err := marshalForDiff(pair.Live)
+	if err != nil {
+		return "", err
+	}
+
+	generated, err := marshalForDiff(pair.Generated)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(live),
+		B:        difflib.SplitLines(generated),
+		FromFile: "live/" + pair.Ref.String(),
+		ToFile:   "generated/" + pair.Ref.String(),
+		Context:  3,
+	})
+}
+
+func marshalForDiff(object interface{}) (string, error) {
+	if object == nil {
+		return "", nil
+	}
+
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/stretchr/testify/assert"
+)
+
+// objectMap returns an object as decoded from JSON.
+func objectMap(t *testing.T, object interface{}) map[string]interface{} {
+	data, err := json.Marshal(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestDiffObjects(t *testing.T) {
+	p := newTestProject()
+
+	objects, err := kubernetes.ConvertToAPI(p.Name, "web", p.Configs["web"])
+	assert.Nil(t, err)
+	generated := kubernetes.NormalizeGenerated(objectMap(t, objects.ReplicationController))
+
+	// Fields populated by the API server are ignored.
+	objects.ReplicationController.ResourceVersion = "42"
+	objects.ReplicationController.Status.Replicas = 1
+	objects.ReplicationController.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
+	live := objectMap(t, objects.ReplicationController)
+
+	ref := controllerRef{"ReplicationController", "web"}
+	diff, err := diffObjects(objectPair{Ref: ref, Live: kubernetes.NormalizeLive(live, generated), Generated: generated})
+	assert.Nil(t, err)
+	assert.Equal(t, "", diff)
+
+	objects.ReplicationController.Spec.Template.Spec.Containers[0].Image = "nginx:1.9"
+	live = objectMap(t, objects.ReplicationController)
+	diff, err = diffObjects(objectPair{Ref: ref, Live: kubernetes.NormalizeLive(live, generated), Generated: generated})
+	assert.Nil(t, err)
+	assert.Contains(t, diff, "--- live/replicationcontroller/web")
+	assert.Contains(t, diff, "+++ generated/replicationcontroller/web")
+	assert.Contains(t, diff, "-      - image: nginx:1.9")
+	assert.Contains(t, diff, "+      - image: nginx")
+}
+
+func TestDiffObjectsOneSided(t *testing.T) {
+	p := newTestProject()
+	objects, err := kubernetes.ConvertToAPI(p.Name, "redis", p.Configs["redis"])
+	assert.Nil(t, err)
+
+	live := kubernetes.NormalizeLive(objectMap(t, objects.ReplicationController), nil)
+	diff, err := diffObjects(objectPair{Ref: controllerRef{"ReplicationController", "redis"}, Live: live})
+	assert.Nil(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(diff), "\n")[3:] {
+		assert.True(t, strings.HasPrefix(line, "-"), line)
+	}
+}
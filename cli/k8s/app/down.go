@@ -0,0 +1,260 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// ProjectKuberDown scales down and removes every kubernetes object of the project.
+func ProjectKuberDown(p *project.Project, c *cli.Context) error {
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+
+	if !c.Bool("yes") {
+		message := fmt.Sprintf("Going to remove all kubernetes objects of project %s", p.Name)
+		if c.Bool("volumes") {
+			message += ", including persistent volume claims"
+		}
+		if !confirm(message) {
+			return nil
+		}
+	}
+
+	selector := kubernetes.ProjectSelector(p)
+	count := 0
+	failed := func(err error, format string, args ...interface{}) bool {
+		if err == nil {
+			return false
+		}
+		if !errors.IsNotFound(err) {
+			logrus.Errorf(format+": %v", append(args, err)...)
+			count++
+		}
+		return true
+	}
+
+	// Autoscalers would scale the controllers back up.
+	autoscalers, err := client.Extensions().HorizontalPodAutoscalers(api.NamespaceDefault).List(selector, fields.Everything())
+	if !failed(err, "Failed to list horizontal pod autoscalers") {
+		for _, hpa := range autoscalers.Items {
+			err := client.Extensions().HorizontalPodAutoscalers(api.NamespaceDefault).Delete(hpa.Name, nil)
+			failed(err, "Failed to remove horizontal pod autoscaler %s", hpa.Name)
+		}
+	}
+
+	// The cron jobs would start new jobs, and the jobs, the ones run by the
+	// cron jobs included, would replace their pods. Their pods are left running
+	// once they are deleted, like the ones of any controller.
+	jobServices := map[string]bool{}
+	for _, batch := range []struct{ version, resource, kind string }{
+		{"batch/v2alpha1", "cronjobs", "cron job"},
+		{"batch/v1", "jobs", "job"},
+	} {
+		path := []string{"/apis", batch.version, "namespaces", api.NamespaceDefault, batch.resource}
+		objects, err := listObjects(client.Get().AbsPath(path...), selector)
+		if failed(err, "Failed to list %ss", batch.kind) {
+			continue
+		}
+		for _, object := range objects {
+			err := client.Delete().AbsPath(append(path, object.Name)...).Do().Error()
+			if !failed(err, "Failed to remove %s %s", batch.kind, object.Name) {
+				jobServices[object.Labels[kubernetes.SERVICE.Str()]] = true
+			}
+		}
+	}
+	for name := range jobServices {
+		pods, err := client.Pods(api.NamespaceDefault).List(kubernetes.ServiceSelector(p, name), fields.Everything())
+		if !failed(err, "Failed to list the pods of job %s", name) {
+			for _, pod := range pods.Items {
+				err := client.Pods(api.NamespaceDefault).Delete(pod.Name, nil)
+				failed(err, "Failed to remove pod %s", pod.Name)
+			}
+		}
+	}
+
+	if err := scaleDownProject(client, selector); err != nil {
+		return fmt.Errorf("Failed to scale down project %s: %v", p.Name, err)
+	}
+
+	if c.IsSet("grace-period") {
+		grace := int64(c.Int("grace-period"))
+		pods, err := client.Pods(api.NamespaceDefault).List(selector, fields.Everything())
+		if !failed(err, "Failed to list pods") {
+			for _, pod := range pods.Items {
+				err := client.Pods(api.NamespaceDefault).Delete(pod.Name, &api.DeleteOptions{GracePeriodSeconds: &grace})
+				failed(err, "Failed to remove pod %s", pod.Name)
+			}
+		}
+	}
+
+	timeout := time.Duration(c.Int("timeout")) * time.Second
+	if err := kubernetes.WaitForPodsGone(client, selector, timeout); err != nil {
+		return fmt.Errorf("Pods of project %s did not terminate within %s", p.Name, timeout)
+	}
+
+	deployments, err := client.Extensions().Deployments(api.NamespaceDefault).List(selector, fields.Everything())
+	if !failed(err, "Failed to list deployments") {
+		for _, dc := range deployments.Items {
+			err := client.Extensions().Deployments(api.NamespaceDefault).Delete(dc.Name, nil)
+			failed(err, "Failed to remove deployment %s", dc.Name)
+		}
+	}
+
+	rcs, err := client.ReplicationControllers(api.NamespaceDefault).List(selector)
+	if !failed(err, "Failed to list replication controllers") {
+		for _, rc := range rcs.Items {
+			err := client.ReplicationControllers(api.NamespaceDefault).Delete(rc.Name)
+			failed(err, "Failed to remove replication controller %s", rc.Name)
+		}
+	}
+
+	services, err := client.Services(api.NamespaceDefault).List(selector)
+	if !failed(err, "Failed to list services") {
+		for _, service := range services.Items {
+			err := client.Services(api.NamespaceDefault).Delete(service.Name)
+			failed(err, "Failed to remove service %s", service.Name)
+		}
+	}
+
+	ingresses, err := client.Extensions().Ingress(api.NamespaceDefault).List(selector, fields.Everything())
+	if !failed(err, "Failed to list ingresses") {
+		for _, ingress := range ingresses.Items {
+			err := client.Extensions().Ingress(api.NamespaceDefault).Delete(ingress.Name, nil)
+			failed(err, "Failed to remove ingress %s", ingress.Name)
+		}
+	}
+
+	configMaps, err := listObjectNames(client.RESTClient, "configmaps", selector)
+	if !failed(err, "Failed to list config maps") {
+		for _, name := range configMaps {
+			err := client.Delete().Namespace(api.NamespaceDefault).Resource("configmaps").Name(name).Do().Error()
+			failed(err, "Failed to remove config map %s", name)
+		}
+	}
+
+	policies, err := listObjectNames(client.ExtensionsClient.RESTClient, "networkpolicies", selector)
+	if !failed(err, "Failed to list network policies") {
+		for _, name := range policies {
+			err := client.ExtensionsClient.Delete().Namespace(api.NamespaceDefault).Resource("networkpolicies").Name(name).Do().Error()
+			failed(err, "Failed to remove network policy %s", name)
+		}
+	}
+
+	secrets, err := client.Secrets(api.NamespaceDefault).List(selector, fields.Everything())
+	if !failed(err, "Failed to list secrets") {
+		for _, secret := range secrets.Items {
+			err := client.Secrets(api.NamespaceDefault).Delete(secret.Name)
+			failed(err, "Failed to remove secret %s", secret.Name)
+		}
+	}
+
+	if c.Bool("volumes") {
+		claims, err := client.PersistentVolumeClaims(api.NamespaceDefault).List(selector, fields.Everything())
+		if !failed(err, "Failed to list persistent volume claims") {
+			for _, claim := range claims.Items {
+				err := client.PersistentVolumeClaims(api.NamespaceDefault).Delete(claim.Name)
+				failed(err, "Failed to remove persistent volume claim %s", claim.Name)
+			}
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("Failed to remove %d objects of project %s", count, p.Name)
+	}
+	return nil
+}
+
+// scaleDownProject sets the replicas of every controller of the project to zero.
+func scaleDownProject(client *client.Client, selector labels.Selector) error {
+	rcs, err := client.ReplicationControllers(api.NamespaceDefault).List(selector)
+	if err != nil {
+		return err
+	}
+	for i := range rcs.Items {
+		rc := &rcs.Items[i]
+		// The deployments scale their own replication controllers.
+		if rc.Spec.Replicas == 0 || kubernetes.IsDeploymentController(rc) {
+			continue
+		}
+		logrus.Infof("Scaling down replication controller %s", rc.Name)
+		rc.Spec.Replicas = 0
+		if _, err := client.ReplicationControllers(api.NamespaceDefault).Update(rc); err != nil {
+			return err
+		}
+	}
+
+	deployments, err := client.Extensions().Deployments(api.NamespaceDefault).List(selector, fields.Everything())
+	if err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		dc := &deployments.Items[i]
+		if dc.Spec.Replicas == 0 {
+			continue
+		}
+		logrus.Infof("Scaling down deployment %s", dc.Name)
+		dc.Spec.Replicas = 0
+		if _, err := client.Extensions().Deployments(api.NamespaceDefault).Update(dc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listObjectNames returns the names of the objects of the resource matching
+// the selector. It is used for the resources that are not part of the vendored
+// API types (config maps, network policies).
+func listObjectNames(rest *client.RESTClient, resource string, selector labels.Selector) ([]string, error) {
+	objects, err := listObjects(rest.Get().Namespace(api.NamespaceDefault).Resource(resource), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, object := range objects {
+		names = append(names, object.Name)
+	}
+	return names, nil
+}
+
+// listObjects returns the metadata of the objects of a list request matching
+// the selector, decoding the list from the raw response. Servers that do not
+// know about the resource yield an empty list.
+func listObjects(request *client.Request, selector labels.Selector) ([]api.ObjectMeta, error) {
+	data, err := request.LabelsSelectorParam(selector).Do().Raw()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	list := struct {
+		Items []struct {
+			Metadata api.ObjectMeta `json:"metadata"`
+		} `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	objects := []api.ObjectMeta{}
+	for _, item := range list.Items {
+		objects = append(objects, item.Metadata)
+	}
+	return objects, nil
+}
@@ -1,14 +1,20 @@
 package app
 
 import (
-    "bytes"
-    "io/ioutil"
-    "os"
-    "strings"
-    "text/template"
-    "regexp"
-
-    "github.com/Sirupsen/logrus"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/transformer"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
 )
 
 /* Ancilliary helper functions to interface with the commands interface */
@@ -18,103 +24,124 @@ import (
  * 127.0.0.1:8080.
  */
 func getK8sServer(file string) string {
-    if len(file) == 0 {
-        file = ".kuberconfig"
-    }
+	if len(file) == 0 {
+		file = ".kuberconfig"
+	}
 
-    roughData,err := ioutil.ReadFile(file)
+	roughData, err := ioutil.ReadFile(file)
 
-    if err != nil {
-        logrus.Debugf("Cannot read server data, defaulting to: 127.0.0.1:8080")
-        return "127.0.0.1:8080"
-    }
+	if err != nil {
+		logrus.Debugf("Cannot read server data, defaulting to: 127.0.0.1:8080")
+		return "127.0.0.1:8080"
+	}
 
-    server := strings.TrimSpace(string(roughData))
-    foundPort, err := regexp.MatchString(".+:[\\d]+", server)
-    if !foundPort || err != nil {
-        server += ":8080"
-    }
+	server := strings.TrimSpace(string(roughData))
+	foundPort, err := regexp.MatchString(".+:[\\d]+", server)
+	if !foundPort || err != nil {
+		server += ":8080"
+	}
 
-    return server
+	return server
 }
 
 /**
- * Generate Helm Chart configuration
+ * APIServer returns the kubernetes server configured with k8s config.
  */
-func generateHelm(filename string, svcname string) error {
-    type ChartDetails struct {
-        Name string
-    }
-
-    dirName := strings.Replace(filename, ".yml", "", 1)
-    details := ChartDetails{dirName}
-    manifestDir := dirName + string(os.PathSeparator) + "manifests"
-    dir,err := os.Open(dirName)
-
-    /* Setup the initial directories/files */
-    if err == nil {
-        _ = dir.Close()
-    }
-
-    if err != nil {
-        err = os.Mkdir(dirName, 0755)
-        if err != nil {
-            return err
-        }
-
-        err = os.Mkdir(manifestDir, 0755)
-        if err != nil {
-            return err
-        }
-
-        /* Create the readme file */
-        readme := "This chart was created by Kompose\n"
-        err = ioutil.WriteFile(dirName + string(os.PathSeparator) + "README.md", []byte(readme), 0644)
-        if err != nil {
-            return err
-        }
-
-        /* Create the Chart.yaml file */
-        chart := `name: {{.Name}}
+func APIServer() string {
+	return getK8sServer("")
+}
+
+/**
+ * NewClient creates the kubernetes client of the commands, for the server found
+ * by getK8sServer. Tests replace it to run the commands against a fake server.
+ */
+var NewClient = func() (*client.Client, error) {
+	return kubernetes.CreateClient(kubernetes.ClientOpts{Host: getK8sServer("")})
+}
+
+/**
+ * Create the kubernetes client of the commands with NewClient.
+ */
+func newK8sClient() (*client.Client, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the kubernetes client: %v", err)
+	}
+	return client, nil
+}
+
+/**
+ * Ask the user to confirm an action on stdin, anything but y or yes declines.
+ */
+func confirm(message string) bool {
+	fmt.Printf("%s. Are you sure? [yN] ", message)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+/**
+ * Generate Helm Chart configuration, with the converted artifacts as manifests.
+ */
+func generateHelm(filename string, artifacts []transformer.Artifact) error {
+	type ChartDetails struct {
+		Name string
+	}
+
+	dirName := strings.Replace(filename, ".yml", "", 1)
+	details := ChartDetails{dirName}
+	manifestDir := dirName + string(os.PathSeparator) + "manifests"
+	dir, err := os.Open(dirName)
+
+	/* Setup the initial directories/files */
+	if err == nil {
+		_ = dir.Close()
+	}
+
+	if err != nil {
+		err = os.Mkdir(dirName, 0755)
+		if err != nil {
+			return err
+		}
+
+		err = os.Mkdir(manifestDir, 0755)
+		if err != nil {
+			return err
+		}
+
+		/* Create the readme file */
+		readme := "This chart was created by Kompose\n"
+		err = ioutil.WriteFile(dirName+string(os.PathSeparator)+"README.md", []byte(readme), 0644)
+		if err != nil {
+			return err
+		}
+
+		/* Create the Chart.yaml file */
+		chart := `name: {{.Name}}
 description: A generated Helm Chart from Skippbox Kompose
 version: 0.0.1
 source:
 home:
 `
 
-        t, err := template.New("ChartTmpl").Parse(chart)
-        if err != nil {
-            logrus.Fatalf("Failed to generate Chart.yaml template: %s\n", err)
-        }
-        var chartData bytes.Buffer
-        _ = t.Execute(&chartData, details)
-
-        err = ioutil.WriteFile(dirName + string(os.PathSeparator) + "Chart.yaml", chartData.Bytes(), 0644)
-        if err != nil {
-            return err
-        }
-    }
-
-    /* Copy all yaml files into the newly created manifests directory */
-    infile, err := ioutil.ReadFile(svcname + "-rc.json")
-    if err != nil {
-        logrus.Infof("Error reading %s: %s\n", svcname + "-rc.yaml", err)
-        return err
-    }
-
-    err = ioutil.WriteFile(manifestDir + string(os.PathSeparator) + svcname + "-rc.json", infile, 0644)
-    if err != nil {
-        return err
-    }
-
-    /* The svc file is optional */
-    infile, err = ioutil.ReadFile(svcname + "-svc.json")
-    if err == nil {
-        err = ioutil.WriteFile(manifestDir + string(os.PathSeparator) + svcname + "-svc.json", infile, 0644)
-        if err != nil {
-           return err
-        }
-    }
-
-    return nil
+		t, err := template.New("ChartTmpl").Parse(chart)
+		if err != nil {
+			return fmt.Errorf("Failed to generate Chart.yaml template: %v", err)
+		}
+		var chartData bytes.Buffer
+		_ = t.Execute(&chartData, details)
+
+		err = ioutil.WriteFile(dirName+string(os.PathSeparator)+"Chart.yaml", chartData.Bytes(), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	/* Copy the generated files into the manifests directory */
+	return transformer.Write(manifestDir, artifacts)
 }
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/codegangsta/cli"
+	cliApp "github.com/docker/libcompose/cli/app"
+	cliLogger "github.com/docker/libcompose/cli/logger"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/logger"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/utils"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+// ProjectKuberLogs streams the logs of the pods of the specified services, or
+// of all services if none is specified.
+func ProjectKuberLogs(p *project.Project, c *cli.Context) error {
+	options, err := parseLogOptions(c.Bool("follow"), c.String("tail"), c.String("since"))
+	if err != nil {
+		return cliApp.NewUsageError("%v", err)
+	}
+
+	names := c.Args()
+	if len(names) == 0 {
+		for name := range p.Configs {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := p.Configs[name]; !ok {
+			return project.NewServiceNotFound(name)
+		}
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+	factory := cliLogger.NewColorLoggerFactory()
+	tasks := utils.InParallel{}
+
+	for _, name := range names {
+		pods, err := client.Pods(api.NamespaceDefault).List(kubernetes.ServiceSelector(p, name), fields.Everything())
+		if err != nil {
+			return &project.ServiceError{Service: name, Err: fmt.Errorf("Failed to list pods: %v", err)}
+		}
+
+		for _, pod := range pods.Items {
+			for _, container := range pod.Spec.Containers {
+				prefix := pod.Name
+				if len(pod.Spec.Containers) > 1 {
+					prefix += "/" + container.Name
+				}
+
+				task := func(pod, container string, l logger.Logger) func() error {
+					return func() error {
+						return kubernetes.StreamPodLogs(client, pod, container, options, l)
+					}
+				}(pod.Name, container.Name, factory.Create(prefix))
+
+				tasks.Add(task)
+			}
+		}
+	}
+
+	return tasks.Wait()
+}
+
+// parseLogOptions parses the tail (a number of lines or "all") and since (a
+// duration such as 10m) command line values.
+func parseLogOptions(follow bool, tail, since string) (kubernetes.LogOptions, error) {
+	options := kubernetes.LogOptions{
+		Follow:    follow,
+		TailLines: -1,
+	}
+
+	if tail != "" && tail != "all" {
+		lines, err := strconv.ParseInt(tail, 10, 64)
+		if err != nil || lines < 0 {
+			return options, fmt.Errorf("Invalid tail value %s, expected a number of lines or all", tail)
+		}
+		options.TailLines = lines
+	}
+
+	if since != "" {
+		duration, err := time.ParseDuration(since)
+		if err != nil || duration <= 0 {
+			return options, fmt.Errorf("Invalid since value %s, expected a duration such as 10m", since)
+		}
+		options.SinceSeconds = int64(duration.Seconds())
+		if options.SinceSeconds == 0 {
+			options.SinceSeconds = 1
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLogOptions(t *testing.T) {
+	options, err := parseLogOptions(true, "all", "")
+	assert.Nil(t, err)
+	assert.Equal(t, kubernetes.LogOptions{Follow: true, TailLines: -1}, options)
+
+	options, err = parseLogOptions(false, "20", "10m")
+	assert.Nil(t, err)
+	assert.Equal(t, kubernetes.LogOptions{TailLines: 20, SinceSeconds: 600}, options)
+
+	options, err = parseLogOptions(false, "", "10ms")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), options.SinceSeconds)
+}
+
+func TestParseLogOptionsInvalid(t *testing.T) {
+	_, err := parseLogOptions(false, "some", "")
+	assert.NotNil(t, err)
+
+	_, err = parseLogOptions(false, "-3", "")
+	assert.NotNil(t, err)
+
+	_, err = parseLogOptions(false, "all", "yesterday")
+	assert.NotNil(t, err)
+}
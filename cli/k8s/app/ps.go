@@ -0,0 +1,219 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+	"github.com/ghodss/yaml"
+
+	"k8s.io/kubernetes/pkg/api"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+// ServiceStatus holds the state of a compose service running on kubernetes.
+type ServiceStatus struct {
+	Service    string                 `json:"service"`
+	Controller string                 `json:"controller,omitempty"`
+	Desired    int                    `json:"desired"`
+	Current    int                    `json:"current"`
+	Ready      int                    `json:"ready"`
+	Pods       []kubernetes.PodStatus `json:"pods,omitempty"`
+	Endpoints  []string               `json:"endpoints,omitempty"`
+}
+
+// ServiceStatusSet holds a list of ServiceStatus.
+type ServiceStatusSet []*ServiceStatus
+
+// collectStatus gathers the controllers, pods and services labelled with the
+// project, grouped by compose service and sorted by service name.
+func collectStatus(client *client.Client, p *project.Project) (ServiceStatusSet, error) {
+	byName := map[string]*ServiceStatus{}
+	get := func(name string) *ServiceStatus {
+		status, ok := byName[name]
+		if !ok {
+			status = &ServiceStatus{Service: name}
+			byName[name] = status
+		}
+		return status
+	}
+
+	for name := range p.Configs {
+		get(name)
+	}
+
+	// A service is counted through its deployment if it has one, the
+	// replication controllers of a deployment being managed by it.
+	deployments, err := client.Extensions().Deployments(api.NamespaceDefault).List(kubernetes.ProjectSelector(p), fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, dc := range deployments.Items {
+		status := get(dc.Labels[kubernetes.SERVICE.Str()])
+		status.Controller = "deployment/" + dc.Name
+		status.Desired += dc.Spec.Replicas
+		status.Current += dc.Status.Replicas
+	}
+
+	rcs, err := client.ReplicationControllers(api.NamespaceDefault).List(kubernetes.ProjectSelector(p))
+	if err != nil {
+		return nil, err
+	}
+	for i := range rcs.Items {
+		rc := &rcs.Items[i]
+		status := get(rc.Labels[kubernetes.SERVICE.Str()])
+		if kubernetes.IsDeploymentController(rc) || strings.HasPrefix(status.Controller, "deployment/") {
+			continue
+		}
+		status.Controller = "rc/" + rc.Name
+		status.Desired += rc.Spec.Replicas
+		status.Current += rc.Status.Replicas
+	}
+
+	pods, err := client.Pods(api.NamespaceDefault).List(kubernetes.ProjectSelector(p), fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := kubernetes.NewPodStatus(&pods.Items[i])
+		status := get(pods.Items[i].Labels[kubernetes.SERVICE.Str()])
+		if pod.Ready {
+			status.Ready++
+		}
+		status.Pods = append(status.Pods, pod)
+	}
+
+	services, err := client.Services(api.NamespaceDefault).List(kubernetes.ProjectSelector(p))
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services.Items {
+		status := get(service.Labels[kubernetes.SERVICE.Str()])
+		status.Endpoints = append(status.Endpoints, serviceEndpoints(&service)...)
+	}
+
+	names := []string{}
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := ServiceStatusSet{}
+	for _, name := range names {
+		status := byName[name]
+		sort.Sort(podsByName(status.Pods))
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// serviceEndpoints returns the addresses a service can be reached on: its
+// cluster IP, node ports and load balancer ingress points.
+func serviceEndpoints(service *api.Service) []string {
+	result := []string{}
+	for _, port := range service.Spec.Ports {
+		proto := strings.ToLower(string(port.Protocol))
+		if service.Spec.ClusterIP != "" && service.Spec.ClusterIP != api.ClusterIPNone {
+			result = append(result, fmt.Sprintf("%s:%d/%s", service.Spec.ClusterIP, port.Port, proto))
+		}
+		if port.NodePort != 0 {
+			result = append(result, fmt.Sprintf("*:%d/%s", port.NodePort, proto))
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			host := ingress.IP
+			if host == "" {
+				host = ingress.Hostname
+			}
+			result = append(result, fmt.Sprintf("%s:%d/%s", host, port.Port, proto))
+		}
+	}
+	return result
+}
+
+type podsByName []kubernetes.PodStatus
+
+func (p podsByName) Len() int           { return len(p) }
+func (p podsByName) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p podsByName) Less(i, j int) bool { return p[i].Name < p[j].Name }
+
+// InfoSet converts the statuses into a project.InfoSet, one row per service.
+// The wide format adds a column with the pods of each service.
+func (statuses ServiceStatusSet) InfoSet(wide bool) project.InfoSet {
+	result := project.InfoSet{}
+	for _, status := range statuses {
+		controller := status.Controller
+		if controller == "" {
+			controller = "<none>"
+		}
+
+		info := project.Info{
+			{Key: "Service", Value: status.Service},
+			{Key: "Controller", Value: controller},
+			{Key: "Desired", Value: strconv.Itoa(status.Desired)},
+			{Key: "Current", Value: strconv.Itoa(status.Current)},
+			{Key: "Ready", Value: strconv.Itoa(status.Ready)},
+			{Key: "Endpoints", Value: strings.Join(status.Endpoints, ",")},
+		}
+
+		if wide {
+			pods := []string{}
+			for _, pod := range status.Pods {
+				pods = append(pods, fmt.Sprintf("%s(%s,%d)", pod.Name, pod.Phase, pod.Restarts))
+			}
+			info = append(info, project.InfoPart{Key: "Pods", Value: strings.Join(pods, ",")})
+		}
+
+		result = append(result, info)
+	}
+	return result
+}
+
+// Format renders the statuses according to the specified output (json, yaml,
+// wide or the default table) or, if not empty, the specified go template which
+// is executed once per service.
+func (statuses ServiceStatusSet) Format(output, format string) (string, error) {
+	if format != "" {
+		tmpl, err := template.New("format").Parse(format)
+		if err != nil {
+			return "", fmt.Errorf("Invalid format template: %v", err)
+		}
+
+		var buffer bytes.Buffer
+		for _, status := range statuses {
+			if err := tmpl.Execute(&buffer, status); err != nil {
+				return "", err
+			}
+			buffer.WriteString("\n")
+		}
+		return buffer.String(), nil
+	}
+
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	case "yaml":
+		data, err := yaml.Marshal(statuses)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "wide":
+		return statuses.InfoSet(true).String(true), nil
+	case "":
+		return statuses.InfoSet(false).String(true), nil
+	}
+
+	return "", fmt.Errorf("Unknown output format %s, expected one of json, yaml or wide", output)
+}
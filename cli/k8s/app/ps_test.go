@@ -0,0 +1,99 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func testStatuses() ServiceStatusSet {
+	return ServiceStatusSet{
+		{
+			Service:    "redis",
+			Controller: "rc/redis",
+			Desired:    2,
+			Current:    2,
+			Ready:      1,
+			Pods: []kubernetes.PodStatus{
+				{Name: "redis-a", Phase: "Running", Ready: true},
+				{Name: "redis-b", Phase: "Pending", Restarts: 3},
+			},
+			Endpoints: []string{"10.0.0.1:6379/tcp"},
+		},
+		{
+			Service: "web",
+		},
+	}
+}
+
+func TestServiceEndpoints(t *testing.T) {
+	service := &api.Service{
+		Spec: api.ServiceSpec{
+			ClusterIP: "10.0.0.1",
+			Ports: []api.ServicePort{
+				{Port: 80, Protocol: api.ProtocolTCP, NodePort: 30080},
+				{Port: 53, Protocol: api.ProtocolUDP},
+			},
+		},
+		Status: api.ServiceStatus{
+			LoadBalancer: api.LoadBalancerStatus{
+				Ingress: []api.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"10.0.0.1:80/tcp",
+		"*:30080/tcp",
+		"lb.example.com:80/tcp",
+		"10.0.0.1:53/udp",
+		"lb.example.com:53/udp",
+	}, serviceEndpoints(service))
+
+	service.Spec.ClusterIP = api.ClusterIPNone
+	service.Status.LoadBalancer.Ingress = nil
+	assert.Equal(t, []string{"*:30080/tcp"}, serviceEndpoints(service))
+}
+
+func TestFormatTable(t *testing.T) {
+	output, err := testStatuses().Format("", "")
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, []string{"Service", "Controller", "Desired", "Current", "Ready", "Endpoints"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"redis", "rc/redis", "2", "2", "1", "10.0.0.1:6379/tcp"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"web", "<none>", "0", "0", "0"}, strings.Fields(lines[2]))
+}
+
+func TestFormatWide(t *testing.T) {
+	output, err := testStatuses().Format("wide", "")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "redis-a(Running,0),redis-b(Pending,3)")
+}
+
+func TestFormatTemplate(t *testing.T) {
+	output, err := testStatuses().Format("json", "{{.Service}}={{.Ready}}/{{.Desired}}")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis=1/2\nweb=0/0\n", output)
+
+	_, err = testStatuses().Format("", "{{.Service")
+	assert.NotNil(t, err)
+}
+
+func TestFormatMachineReadable(t *testing.T) {
+	output, err := testStatuses().Format("json", "")
+	assert.Nil(t, err)
+	assert.Contains(t, output, `"controller": "rc/redis"`)
+
+	output, err = testStatuses().Format("yaml", "")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "controller: rc/redis")
+
+	_, err = testStatuses().Format("xml", "")
+	assert.NotNil(t, err)
+}
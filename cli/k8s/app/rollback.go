@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/codegangsta/cli"
+	cliApp "github.com/docker/libcompose/cli/app"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+// ProjectKuberRollback rolls the deployment of a service back to the revision
+// given by --to-revision, or to the previous revision, then prints its history.
+func ProjectKuberRollback(p *project.Project, c *cli.Context) error {
+	if len(c.Args()) != 1 {
+		return cliApp.NewUsageError("Please specify the service to roll back")
+	}
+
+	name := c.Args()[0]
+	if _, ok := p.Configs[name]; !ok {
+		return project.NewServiceNotFound(name)
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+
+	deployment, err := serviceDeployment(client, p, name)
+	if err != nil {
+		return &project.ServiceError{Service: name, Err: err}
+	}
+
+	revision := int64(c.Int("to-revision"))
+	data := map[string]string{"revision": strconv.FormatInt(revision, 10)}
+
+	p.Notify(project.EventServiceRollbackStart, name, data)
+	if err := kubernetes.RollbackDeployment(client, deployment, revision); err != nil {
+		return &project.ServiceError{Service: name, Err: fmt.Errorf("Failed to roll back: %v", err)}
+	}
+	p.Notify(project.EventServiceRollback, name, data)
+
+	return printHistory(client, p, []string{name})
+}
+
+// ProjectKuberHistory prints the revisions of the deployments of the specified
+// services, or of all the services of the project.
+func ProjectKuberHistory(p *project.Project, c *cli.Context) error {
+	names, err := serviceArgs(p, c.Args())
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+
+	return printHistory(client, p, names)
+}
+
+// serviceDeployment returns the name of the deployment of a service.
+func serviceDeployment(client *client.Client, p *project.Project, name string) (string, error) {
+	deployments, err := client.Extensions().Deployments(api.NamespaceDefault).List(kubernetes.ServiceSelector(p, name), fields.Everything())
+	if err != nil {
+		return "", err
+	}
+	if len(deployments.Items) == 0 {
+		return "", fmt.Errorf("No deployment, only services converted with --deployment keep a revision history")
+	}
+	return deployments.Items[0].Name, nil
+}
+
+// printHistory prints the history of the services which it could get, and
+// returns the errors of the other ones.
+func printHistory(client *client.Client, p *project.Project, names []string) error {
+	infos := project.InfoSet{}
+	results := map[string]error{}
+	for _, name := range names {
+		revisions, err := kubernetes.RevisionHistory(client, kubernetes.ServiceSelector(p, name))
+		if err != nil {
+			results[name] = fmt.Errorf("Failed to get the history: %v", err)
+			continue
+		}
+		results[name] = nil
+		infos = append(infos, historyInfo(name, revisions)...)
+	}
+
+	fmt.Print(infos.String(true))
+	return project.NewActionError(results)
+}
+
+// historyInfo converts the revisions of a service into one row per revision.
+func historyInfo(name string, revisions []kubernetes.Revision) project.InfoSet {
+	result := project.InfoSet{}
+	for _, revision := range revisions {
+		result = append(result, project.Info{
+			{Key: "Service", Value: name},
+			{Key: "Revision", Value: strconv.FormatInt(revision.Revision, 10)},
+			{Key: "Hash", Value: revision.Hash},
+			{Key: "ReplicaSet", Value: revision.ReplicaSet},
+			{Key: "Replicas", Value: strconv.Itoa(revision.Replicas)},
+		})
+	}
+	return result
+}
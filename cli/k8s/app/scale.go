@@ -0,0 +1,213 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	cliApp "github.com/docker/libcompose/cli/app"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+// controllerRef identifies a scalable object by its kind and name.
+type controllerRef struct {
+	Kind, Name string
+}
+
+func (r controllerRef) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Name
+}
+
+// ProjectKuberScale scales the controllers of the specified services, given as SERVICE=NUM arguments.
+// A service failing to scale does not stop the other ones.
+func ProjectKuberScale(p *project.Project, c *cli.Context) error {
+	order, serviceScale, err := parseScaleArgs(p, c.Args())
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(c.Int("timeout")) * time.Second
+
+	results := map[string]error{}
+	for _, name := range order {
+		results[name] = scaleService(client, p, name, serviceScale[name])
+		if results[name] != nil {
+			logrus.Errorf("Failed to set the scale %s=%d: %v", name, serviceScale[name], results[name])
+		}
+	}
+
+	if c.Bool("wait") {
+		for _, name := range order {
+			if results[name] != nil {
+				continue
+			}
+			scale := serviceScale[name]
+			pods, err := waitForReadyPods(client, kubernetes.ServiceSelector(p, name), scale, timeout)
+			if err != nil {
+				summary := []string{}
+				for _, pod := range pods {
+					summary = append(summary, fmt.Sprintf("  %s: %s, ready=%t, restarts=%d %s", pod.Name, pod.Phase, pod.Ready, pod.Restarts, pod.Reason))
+				}
+				results[name] = fmt.Errorf("Did not reach %d ready pods within %s", scale, timeout)
+				logrus.Errorf("Service %s did not reach %d ready pods within %s:\n%s", name, scale, timeout, strings.Join(summary, "\n"))
+				continue
+			}
+			logrus.Infof("Service %s has %d ready pods", name, scale)
+		}
+	}
+
+	return project.NewActionError(results)
+}
+
+// scaleService sets the replicas of all the controllers of a service.
+func scaleService(client *client.Client, p *project.Project, name string, scale int) error {
+	controllers, err := findControllers(client, kubernetes.ServiceSelector(p, name))
+	if err != nil {
+		return err
+	}
+	if len(controllers) == 0 {
+		return fmt.Errorf("No controller found")
+	}
+
+	for _, ref := range controllers {
+		logrus.Infof("Setting scale %s=%d (%s)...", name, scale, ref)
+		if err := scaleController(client, ref, scale); err != nil {
+			return fmt.Errorf("Failed to scale %s: %v", ref, err)
+		}
+	}
+	return nil
+}
+
+// parseScaleArgs parses SERVICE=NUM arguments, keeping the order of the services.
+func parseScaleArgs(p *project.Project, args []string) ([]string, map[string]int, error) {
+	order := []string{}
+	serviceScale := map[string]int{}
+
+	if len(args) == 0 {
+		return nil, nil, cliApp.NewUsageError("Please pass arguments in the form: SERVICE=NUM...")
+	}
+
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			return nil, nil, cliApp.NewUsageError("Invalid scale parameter: %s", arg)
+		}
+
+		name := kv[0]
+
+		count, err := strconv.Atoi(kv[1])
+		if err != nil || count < 0 {
+			return nil, nil, cliApp.NewUsageError("Invalid scale parameter: %s", arg)
+		}
+
+		if _, ok := p.Configs[name]; !ok {
+			return nil, nil, project.NewServiceNotFound(name)
+		}
+
+		if _, ok := serviceScale[name]; !ok {
+			order = append(order, name)
+		}
+		serviceScale[name] = count
+	}
+
+	return order, serviceScale, nil
+}
+
+// findControllers returns the scalable objects matching the selector: the
+// deployments if there are some, otherwise the replication controllers which
+// are not managed by a deployment. Jobs run to completion and are not scaled.
+func findControllers(client *client.Client, selector labels.Selector) ([]controllerRef, error) {
+	result := []controllerRef{}
+
+	deployments, err := client.Extensions().Deployments(api.NamespaceDefault).List(selector, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, dc := range deployments.Items {
+		result = append(result, controllerRef{"Deployment", dc.Name})
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	rcs, err := client.ReplicationControllers(api.NamespaceDefault).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rcs.Items {
+		if !kubernetes.IsDeploymentController(&rcs.Items[i]) {
+			result = append(result, controllerRef{"ReplicationController", rcs.Items[i].Name})
+		}
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	jobs, err := client.Extensions().Jobs(api.NamespaceDefault).List(selector, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs.Items) > 0 {
+		return nil, fmt.Errorf("Job %s runs to completion and cannot be scaled", jobs.Items[0].Name)
+	}
+
+	return result, nil
+}
+
+// scaleController updates the replicas of the controller through its scale subresource.
+func scaleController(client *client.Client, ref controllerRef, replicas int) error {
+	scales := client.Extensions().Scales(api.NamespaceDefault)
+
+	s, err := scales.Get(ref.Kind, ref.Name)
+	if err != nil {
+		return err
+	}
+
+	s.Spec.Replicas = replicas
+
+	_, err = scales.Update(ref.Kind, s)
+	return err
+}
+
+// waitForReadyPods polls until exactly count pods match the selector and all of
+// them are ready. It returns the last observed pods so callers can report them.
+func waitForReadyPods(client *client.Client, selector labels.Selector, count int, timeout time.Duration) ([]kubernetes.PodStatus, error) {
+	var last []kubernetes.PodStatus
+	err := wait.PollImmediate(kubernetes.PollInterval, timeout, func() (bool, error) {
+		pods, err := client.Pods(api.NamespaceDefault).List(selector, fields.Everything())
+		if err != nil {
+			return false, err
+		}
+
+		last = []kubernetes.PodStatus{}
+		ready := 0
+		for i := range pods.Items {
+			if pods.Items[i].DeletionTimestamp != nil {
+				continue
+			}
+			status := kubernetes.NewPodStatus(&pods.Items[i])
+			if status.Ready {
+				ready++
+			}
+			last = append(last, status)
+		}
+
+		logrus.Debugf("%d/%d pods ready", ready, count)
+		return ready == count && len(last) == count, nil
+	})
+	return last, err
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProject() *project.Project {
+	p := project.NewProject(&project.Context{})
+	p.Name = "demo"
+	p.Configs["web"] = &project.ServiceConfig{Image: "nginx"}
+	p.Configs["redis"] = &project.ServiceConfig{Image: "redis"}
+	return p
+}
+
+func TestParseScaleArgs(t *testing.T) {
+	p := newTestProject()
+
+	order, scales, err := parseScaleArgs(p, []string{"web=3", "redis=0", "web=5"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"web", "redis"}, order)
+	assert.Equal(t, map[string]int{"web": 5, "redis": 0}, scales)
+}
+
+func TestParseScaleArgsInvalid(t *testing.T) {
+	p := newTestProject()
+
+	for _, args := range [][]string{
+		{},
+		{"web"},
+		{"web=three"},
+		{"web=-1"},
+		{"db=1"},
+	} {
+		_, _, err := parseScaleArgs(p, args)
+		assert.NotNil(t, err, "%v", args)
+	}
+}
+
+func TestControllerRefString(t *testing.T) {
+	assert.Equal(t, "deployment/web", controllerRef{"Deployment", "web"}.String())
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+// ProjectKuberStatus watches the rollout of the specified services, or of all
+// the services of the project, until their pods are updated and available.
+// The progress is reported through the project events. Jobs run to completion
+// rather than being rolled out, they are skipped.
+func ProjectKuberStatus(p *project.Project, c *cli.Context) error {
+	names, err := serviceArgs(p, c.Args())
+	if err != nil {
+		return err
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(c.Int("timeout")) * time.Second
+
+	results := map[string]error{}
+	for _, name := range names {
+		job, err := kubernetes.ParseJob(name, p.Configs[name])
+		if err != nil {
+			results[name] = err
+			continue
+		} else if job != nil {
+			logrus.Infof("Service %s is a job and has no rollout", name)
+			continue
+		}
+
+		results[name] = waitForRollout(client, p, name, timeout)
+		if results[name] != nil {
+			logrus.Errorf("Rollout of service %s failed: %v", name, results[name])
+		}
+	}
+	return project.NewActionError(results)
+}
+
+// serviceArgs returns the services passed as arguments, or all the services of
+// the project sorted by name.
+func serviceArgs(p *project.Project, args []string) ([]string, error) {
+	if len(args) == 0 {
+		names := []string{}
+		for name := range p.Configs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	for _, name := range args {
+		if _, ok := p.Configs[name]; !ok {
+			return nil, project.NewServiceNotFound(name)
+		}
+	}
+	return args, nil
+}
+
+// rolloutTarget returns the desired replicas and the configuration hash of the
+// pod template of the controller of a service.
+func rolloutTarget(client *client.Client, p *project.Project, name string) (int, string, error) {
+	selector := kubernetes.ServiceSelector(p, name)
+
+	deployments, err := client.Extensions().Deployments(api.NamespaceDefault).List(selector, fields.Everything())
+	if err != nil {
+		return 0, "", err
+	}
+	if len(deployments.Items) > 0 {
+		dc := deployments.Items[0]
+		return dc.Spec.Replicas, dc.Spec.Template.Labels[kubernetes.HASH.Str()], nil
+	}
+
+	rcs, err := client.ReplicationControllers(api.NamespaceDefault).List(selector)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(rcs.Items) > 0 {
+		rc := rcs.Items[0]
+		return rc.Spec.Replicas, rc.Spec.Template.Labels[kubernetes.HASH.Str()], nil
+	}
+
+	return 0, "", fmt.Errorf("No controller found for service %s", name)
+}
+
+// waitForRollout polls the pods of a service until its rollout is done,
+// notifying the progress whenever the status changes.
+func waitForRollout(client *client.Client, p *project.Project, name string, timeout time.Duration) error {
+	desired, hash, err := rolloutTarget(client, p, name)
+	if err != nil {
+		return err
+	}
+
+	var last *kubernetes.RolloutStatus
+	err = wait.PollImmediate(kubernetes.PollInterval, timeout, func() (bool, error) {
+		pods, err := client.Pods(api.NamespaceDefault).List(kubernetes.ServiceSelector(p, name), fields.Everything())
+		if err != nil {
+			return false, err
+		}
+
+		status := kubernetes.NewRolloutStatus(pods.Items, hash, desired)
+		if last == nil || *last != status {
+			last = &status
+			if !status.Done() {
+				p.Notify(project.EventServiceRolloutProgress, name, status.Data())
+			}
+		}
+
+		return status.Done(), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("%d/%d pods updated and %d available after %s", last.Updated, last.Desired, last.Available, timeout)
+	} else if err != nil {
+		return err
+	}
+
+	p.Notify(project.EventServiceRollout, name, last.Data())
+	return nil
+}
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+// ProjectKuberUp creates or updates the kubernetes objects of the services.
+// With --watch, the objects are the ones k8s convert generates, with the same
+// flags: they are applied, then the files the project is loaded from are
+// watched and the objects that changed are applied again until interrupted.
+func ProjectKuberUp(p *project.Project, c *cli.Context) error {
+	if !c.Bool("watch") {
+		return p.Up(c.Args()...)
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+
+	options := convertOptions(c)
+	if err := applyProject(client, p, options, c.Args()); err != nil {
+		return err
+	}
+
+	logrus.Infof("Watching %v", p.Files)
+
+	last := modTimes(p.Files)
+	for {
+		time.Sleep(kubernetes.PollInterval)
+
+		current := modTimes(p.Files)
+		if !changed(last, current) {
+			continue
+		}
+		last = current
+
+		logrus.Infof("Compose files changed, applying project %s", p.Name)
+		if err := p.Reload(); err != nil {
+			logrus.Errorf("Failed to reload project %s, keeping the previous configuration: %v", p.Name, err)
+			continue
+		}
+
+		if err := applyProject(client, p, options, c.Args()); err != nil {
+			logrus.Errorf("Failed to apply project %s: %v", p.Name, err)
+		}
+
+		// Files may have been added or removed from the project.
+		last = modTimes(p.Files)
+	}
+}
+
+// applyProject converts the project, or the specified services, with the
+// kubernetes transformer and applies the objects that differ from the ones on
+// the cluster. An object failing to apply does not stop the other ones.
+func applyProject(client *client.Client, p *project.Project, options transformer.Options, services []string) error {
+	artifacts, err := transformer.Convert(p, "kubernetes", options)
+	if err != nil {
+		return err
+	}
+
+	selected := map[string]bool{}
+	for _, name := range services {
+		if _, ok := p.Configs[name]; !ok {
+			return project.NewServiceNotFound(name)
+		}
+		selected[name] = true
+	}
+
+	count := 0
+	for _, artifact := range artifacts {
+		if len(selected) > 0 && artifact.Service != "" && !selected[artifact.Service] {
+			continue
+		}
+		if err := applyArtifact(client, p, artifact); err != nil {
+			logrus.Errorf("Failed to apply %s: %v", artifact.Name, err)
+			count++
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("Failed to apply %d objects of project %s", count, p.Name)
+	}
+	return nil
+}
+
+// applyArtifact creates the object of an artifact if it is missing from the
+// cluster, or updates it if it differs from the live one on the fields kompose
+// sets, as diff compares them. The scale of the controllers is kept. Since
+// replication controllers do not roll their pods and the pod template of jobs
+// cannot be changed, their pods are replaced and the jobs recreated.
+func applyArtifact(client *client.Client, p *project.Project, artifact transformer.Artifact) error {
+	object, err := decodeArtifact(artifact)
+	if err != nil {
+		return err
+	}
+
+	live, err := getObject(client, object.Path, object.Ref.Name)
+	if err != nil {
+		return err
+	}
+	if live == nil {
+		logrus.Infof("Creating %s", object.Ref)
+		return client.Post().AbsPath(object.Path...).Body(object.Data).Do().Error()
+	}
+
+	liveMeta, _ := live["metadata"].(map[string]interface{})
+	liveLabels, _ := liveMeta["labels"].(map[string]interface{})
+	if liveLabels[kubernetes.PROJECT.Str()] != p.Name {
+		return fmt.Errorf("%s does not belong to project %s", object.Ref, p.Name)
+	}
+
+	generated := kubernetes.NormalizeGenerated(object.Object)
+	if reflect.DeepEqual(kubernetes.NormalizeLive(live, generated), generated) {
+		return nil
+	}
+
+	if object.Ref.Kind == "Job" {
+		logrus.Infof("Recreating %s", object.Ref)
+		if err := client.Delete().AbsPath(objectPath(object.Path, object.Ref.Name)...).Do().Error(); err != nil {
+			return err
+		}
+		if err := deleteServicePods(client, p, artifact.Service); err != nil {
+			return err
+		}
+		return client.Post().AbsPath(object.Path...).Body(object.Data).Do().Error()
+	}
+
+	logrus.Infof("Updating %s", object.Ref)
+	metadata, _ := object.Object["metadata"].(map[string]interface{})
+	metadata["resourceVersion"] = liveMeta["resourceVersion"]
+	spec, _ := object.Object["spec"].(map[string]interface{})
+	liveSpec, _ := live["spec"].(map[string]interface{})
+	switch object.Ref.Kind {
+	case "ReplicationController", "Deployment":
+		spec["replicas"] = liveSpec["replicas"]
+	case "Service":
+		spec["clusterIP"] = liveSpec["clusterIP"]
+	}
+
+	data, err := json.Marshal(object.Object)
+	if err != nil {
+		return err
+	}
+	if err := client.Put().AbsPath(objectPath(object.Path, object.Ref.Name)...).Body(data).Do().Error(); err != nil {
+		return err
+	}
+
+	if object.Ref.Kind == "ReplicationController" {
+		return deleteServicePods(client, p, artifact.Service)
+	}
+	return nil
+}
+
+// deleteServicePods deletes the pods of a service, for its controller to
+// replace them.
+func deleteServicePods(client *client.Client, p *project.Project, name string) error {
+	pods, err := client.Pods(api.NamespaceDefault).List(kubernetes.ServiceSelector(p, name), fields.Everything())
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if err := client.Pods(api.NamespaceDefault).Delete(pod.Name, nil); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// modTimes returns the modification time of the files, missing files have a
+// zero time.
+func modTimes(files []string) map[string]time.Time {
+	result := map[string]time.Time{}
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			result[file] = info.ModTime()
+		} else {
+			result[file] = time.Time{}
+		}
+	}
+	return result
+}
+
+func changed(before, after map[string]time.Time) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for file, t := range after {
+		if previous, ok := before[file]; !ok || !previous.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/transformer"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+func TestChanged(t *testing.T) {
+	now := time.Now()
+	before := map[string]time.Time{"docker-compose.yml": now}
+
+	assert.False(t, changed(before, map[string]time.Time{"docker-compose.yml": now}))
+	assert.True(t, changed(before, map[string]time.Time{"docker-compose.yml": now.Add(time.Second)}))
+	assert.True(t, changed(before, map[string]time.Time{"docker-compose.yml": now, "web.env": now}))
+	assert.True(t, changed(before, map[string]time.Time{"common.yml": now}))
+}
+
+func TestModTimesMissingFile(t *testing.T) {
+	times := modTimes([]string{"does-not-exist.yml"})
+	assert.True(t, times["does-not-exist.yml"].IsZero())
+}
+
+func TestApplyProject(t *testing.T) {
+	s, done := newFakeServer(t)
+	defer done()
+
+	p := newTestProject()
+	p.Configs["web"].Image = "nginx:1.9"
+	p.Configs["web"].Ports = []string{"80"}
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis"})
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{kubernetes.AutoscaleMaxLabel: "3"})
+	p.Configs["redis"].Ports = []string{"6379"}
+	p.Configs["migrate"] = &project.ServiceConfig{
+		Image:  "web",
+		Labels: project.NewSliceorMap(map[string]string{kubernetes.JobLabel: "true"}),
+	}
+	options := transformer.Options{NetworkPolicies: true}
+
+	assert.Nil(t, applyProject(s.Client(), p, options, nil))
+	assert.Equal(t, []string{"redis", "web"}, s.Names("replicationcontrollers"))
+	assert.Equal(t, []string{"redis", "web"}, s.Names("services"))
+	assert.Equal(t, []string{"web"}, s.Names("horizontalpodautoscalers"))
+	assert.Equal(t, []string{"migrate", "redis", "web"}, s.Names("networkpolicies"))
+	assert.Equal(t, []string{"migrate"}, s.Names("jobs"))
+	webPods, err := s.Client().Pods(api.NamespaceDefault).List(kubernetes.ServiceSelector(p, "web"), fields.Everything())
+	assert.Nil(t, err)
+
+	// Applying the same objects again leaves them alone.
+	requests := len(s.Requests())
+	assert.Nil(t, applyProject(s.Client(), p, options, nil))
+	for _, request := range s.Requests()[requests:] {
+		assert.True(t, strings.HasPrefix(request, "GET "), request)
+	}
+
+	// Only the changed objects are updated, the scale of the controllers is
+	// kept and the pods of the replication controller are replaced.
+	rcs := s.Client().ReplicationControllers(api.NamespaceDefault)
+	rc, err := rcs.Get("web")
+	assert.Nil(t, err)
+	rc.Spec.Replicas = 2
+	_, err = rcs.Update(rc)
+	assert.Nil(t, err)
+	p.Configs["web"].Image = "nginx:1.10"
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{kubernetes.AutoscaleMaxLabel: "5"})
+	p.Configs["migrate"].Command = project.NewCommand("./manage.py", "migrate")
+
+	requests = len(s.Requests())
+	assert.Nil(t, applyProject(s.Client(), p, options, nil))
+	updates := []string{}
+	for _, request := range s.Requests()[requests:] {
+		if !strings.HasPrefix(request, "GET ") && !strings.HasSuffix(request, "/pods") && !strings.Contains(request, "/pods/") {
+			updates = append(updates, request)
+		}
+	}
+	assert.Equal(t, []string{
+		"DELETE /apis/batch/v1/namespaces/default/jobs/migrate",
+		"POST /apis/batch/v1/namespaces/default/jobs",
+		"PUT /apis/extensions/v1beta1/namespaces/default/networkpolicies/migrate",
+		"PUT /apis/extensions/v1beta1/namespaces/default/horizontalpodautoscalers/web",
+		"PUT /apis/extensions/v1beta1/namespaces/default/networkpolicies/web",
+		"PUT /api/v1/namespaces/default/replicationcontrollers/web",
+		"PUT /api/v1/namespaces/default/services/web",
+	}, updates)
+
+	rc, err = rcs.Get("web")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rc.Spec.Replicas)
+	assert.Equal(t, "nginx:1.10", rc.Spec.Template.Spec.Containers[0].Image)
+	for _, pod := range webPods.Items {
+		assert.NotContains(t, s.Names("pods"), pod.Name)
+	}
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"github.com/codegangsta/cli"
+	"github.com/docker/libcompose/cli/command"
+	k8sApp "github.com/docker/libcompose/cli/k8s/app"
+	"github.com/docker/libcompose/cli/logger"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+)
+
+// ProjectFactory is a struct that hold the app.ProjectFactory implementation.
+type ProjectFactory struct {
+}
+
+// Create implements ProjectFactory.Create using a kubernetes client.
+func (p *ProjectFactory) Create(c *cli.Context) (*project.Project, error) {
+	context := &kubernetes.Context{}
+	context.LoggerFactory = logger.NewColorLoggerFactory()
+	command.Populate(&context.Context, c)
+
+	// k8s convert has its own compose file flag, which takes precedence.
+	if c.IsSet("file") {
+		context.ComposeFile = c.String("file")
+	}
+
+	client, err := k8sApp.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	context.Client = client
+
+	return kubernetes.NewProject(context)
+}
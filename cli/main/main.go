@@ -7,11 +7,16 @@ import (
 	cliApp "github.com/docker/libcompose/cli/app"
 	"github.com/docker/libcompose/cli/command"
 	dockerApp "github.com/docker/libcompose/cli/docker/app"
+	kubernetesApp "github.com/docker/libcompose/cli/kubernetes/app"
 	"github.com/docker/libcompose/version"
 )
 
 func main() {
-	factory := &dockerApp.ProjectFactory{}
+	kubernetesFactory := &kubernetesApp.ProjectFactory{}
+	factory := cliApp.BackendFactory{
+		"docker":     &dockerApp.ProjectFactory{},
+		"kubernetes": kubernetesFactory,
+	}
 
 	app := cli.NewApp()
 	app.Name = "kompose"
@@ -21,6 +26,12 @@ func main() {
 	app.Email = "https://github.com/skippbox/kompose"
 	app.Before = cliApp.BeforeApp
 	app.Flags = append(command.CommonFlags(), dockerApp.DockerClientFlags()...)
+	app.Flags = append(app.Flags, cli.StringFlag{
+		Name:   "backend",
+		Usage:  "Run the services with docker or kubernetes",
+		Value:  "docker",
+		EnvVar: "KOMPOSE_BACKEND",
+	})
 	app.Commands = []cli.Command{
 		command.BuildCommand(factory),
 		command.CreateCommand(factory),
@@ -34,7 +45,8 @@ func main() {
 		command.PullCommand(factory),
 		command.KillCommand(factory),
 		command.PortCommand(factory),
-		command.KuberCommand(factory),
+		command.ConvertCommand(kubernetesFactory),
+		command.KuberCommand(kubernetesFactory),
 		command.PsCommand(factory),
 		command.KuberConfigCommand(factory),
 		command.PauseCommand(factory),
@@ -0,0 +1,144 @@
+package kubernetes
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+)
+
+// Autoscale labels hold the bounds of the replicas of a service and its
+// target CPU utilization, in percent of its CPU request.
+const (
+	AutoscaleMinLabel = "kompose.autoscale.min"
+	AutoscaleMaxLabel = "kompose.autoscale.max"
+	AutoscaleCPULabel = "kompose.autoscale.cpu"
+)
+
+func init() {
+	RegisterDirective(AutoscaleMinLabel, AutoscaleMaxLabel, AutoscaleCPULabel)
+}
+
+// DefaultTargetCPU is the target CPU utilization used when the cpu label is not set.
+const DefaultTargetCPU = 80
+
+// Autoscale holds the autoscaling configuration of a service.
+type Autoscale struct {
+	Min, Max, CPU int
+}
+
+// ParseAutoscale returns the autoscaling configuration set by the labels of a
+// service, or nil if it is not autoscaled.
+func ParseAutoscale(name string, c *project.ServiceConfig) (*Autoscale, error) {
+	labels := c.Labels.MapParts()
+
+	value, ok := labels[AutoscaleMaxLabel]
+	if !ok {
+		for _, label := range []string{AutoscaleMinLabel, AutoscaleCPULabel} {
+			if _, ok := labels[label]; ok {
+				return nil, project.NewValidationError(name, project.LabelField(label), "%s is not set", AutoscaleMaxLabel)
+			}
+		}
+		return nil, nil
+	}
+
+	autoscale := &Autoscale{Min: 1, CPU: DefaultTargetCPU}
+
+	var err error
+	if autoscale.Max, err = autoscaleLabel(name, AutoscaleMaxLabel, value); err != nil {
+		return nil, err
+	}
+	if value, ok := labels[AutoscaleMinLabel]; ok {
+		if autoscale.Min, err = autoscaleLabel(name, AutoscaleMinLabel, value); err != nil {
+			return nil, err
+		}
+	}
+	if value, ok := labels[AutoscaleCPULabel]; ok {
+		if autoscale.CPU, err = autoscaleLabel(name, AutoscaleCPULabel, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if autoscale.Min > autoscale.Max {
+		return nil, project.NewValidationError(name, project.LabelField(AutoscaleMinLabel), "%d is greater than %s (%d)", autoscale.Min, AutoscaleMaxLabel, autoscale.Max)
+	}
+
+	if c.CPUShares == 0 {
+		logrus.Warnf("Service %s is autoscaled but has no CPU request, set cpu_shares so the autoscaler can compute its CPU utilization", name)
+	}
+
+	return autoscale, nil
+}
+
+func autoscaleLabel(name, label, value string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || number < 1 {
+		return 0, project.NewValidationError(name, project.LabelField(label), "must be a positive number, got %s", value)
+	}
+	return number, nil
+}
+
+// ConvertToAutoscaler converts the autoscaling configuration of a service to
+// an horizontal pod autoscaler of its controller, of the specified kind
+// (ReplicationController or Deployment).
+func ConvertToAutoscaler(projectName, name string, c *project.ServiceConfig, autoscale *Autoscale, kind string) *extensions.HorizontalPodAutoscaler {
+	apiVersion := "v1"
+	if kind == "Deployment" {
+		apiVersion = "extensions/v1beta1"
+	}
+
+	min := autoscale.Min
+	return &extensions.HorizontalPodAutoscaler{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "HorizontalPodAutoscaler",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:   name,
+			Labels: ServiceLabels(projectName, name, c),
+		},
+		Spec: extensions.HorizontalPodAutoscalerSpec{
+			ScaleRef: extensions.SubresourceReference{
+				Kind:        kind,
+				Name:        name,
+				APIVersion:  apiVersion,
+				Subresource: "scale",
+			},
+			MinReplicas: &min,
+			MaxReplicas: autoscale.Max,
+			CPUUtilization: &extensions.CPUTargetUtilization{
+				TargetPercentage: autoscale.CPU,
+			},
+		},
+	}
+}
+
+// resources converts the cpu shares and memory limit of a service to the
+// resource requirements of its container. A CPU is worth 1024 shares.
+func resources(c *project.ServiceConfig) api.ResourceRequirements {
+	requirements := api.ResourceRequirements{}
+
+	if c.CPUShares > 0 {
+		requirements.Requests = api.ResourceList{
+			api.ResourceCPU: *resource.NewMilliQuantity(c.CPUShares*1000/1024, resource.DecimalSI),
+		}
+	}
+
+	if c.MemLimit > 0 {
+		if requirements.Requests == nil {
+			requirements.Requests = api.ResourceList{}
+		}
+		requirements.Requests[api.ResourceMemory] = *resource.NewQuantity(c.MemLimit, resource.BinarySI)
+		requirements.Limits = api.ResourceList{
+			api.ResourceMemory: *resource.NewQuantity(c.MemLimit, resource.BinarySI),
+		}
+	}
+
+	return requirements
+}
@@ -0,0 +1,73 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestConvertToAutoscaler(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image:     "nginx",
+		CPUShares: 512,
+		MemLimit:  64 * 1024 * 1024,
+		Labels: project.NewSliceorMap(map[string]string{
+			AutoscaleMinLabel: "2",
+			AutoscaleMaxLabel: "10",
+			AutoscaleCPULabel: "70",
+		}),
+	}
+
+	objects, err := ConvertToAPI("demo", "web", sc)
+	assert.Nil(t, err)
+	assert.Equal(t, &Autoscale{Min: 2, Max: 10, CPU: 70}, objects.Autoscale)
+	assert.Equal(t, 2, objects.ReplicationController.Spec.Replicas)
+	assert.Equal(t, 2, objects.Deployment.Spec.Replicas)
+
+	container := objects.ReplicationController.Spec.Template.Spec.Containers[0]
+	cpu := container.Resources.Requests[api.ResourceCPU]
+	memory := container.Resources.Limits[api.ResourceMemory]
+	assert.Equal(t, "500m", cpu.String())
+	assert.Equal(t, "64Mi", memory.String())
+
+	hpa := ConvertToAutoscaler("demo", "web", sc, objects.Autoscale, "Deployment")
+	assert.Equal(t, "Deployment", hpa.Spec.ScaleRef.Kind)
+	assert.Equal(t, "extensions/v1beta1", hpa.Spec.ScaleRef.APIVersion)
+	assert.Equal(t, "scale", hpa.Spec.ScaleRef.Subresource)
+	assert.Equal(t, 2, *hpa.Spec.MinReplicas)
+	assert.Equal(t, 10, hpa.Spec.MaxReplicas)
+	assert.Equal(t, 70, hpa.Spec.CPUUtilization.TargetPercentage)
+}
+
+func TestParseAutoscale(t *testing.T) {
+	autoscale, err := ParseAutoscale("web", &project.ServiceConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, autoscale)
+
+	autoscale, err = ParseAutoscale("web", &project.ServiceConfig{
+		Labels: project.NewSliceorMap(map[string]string{AutoscaleMaxLabel: "3"}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &Autoscale{Min: 1, Max: 3, CPU: DefaultTargetCPU}, autoscale)
+
+	// Pods may use more CPU than they request.
+	autoscale, err = ParseAutoscale("web", &project.ServiceConfig{
+		Labels: project.NewSliceorMap(map[string]string{AutoscaleMaxLabel: "3", AutoscaleCPULabel: "150"}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 150, autoscale.CPU)
+
+	for _, labels := range []map[string]string{
+		{AutoscaleMinLabel: "2"},
+		{AutoscaleMaxLabel: "many"},
+		{AutoscaleMaxLabel: "2", AutoscaleMinLabel: "3"},
+		{AutoscaleMaxLabel: "2", AutoscaleCPULabel: "0"},
+		{AutoscaleMaxLabel: "0"},
+	} {
+		_, err := ParseAutoscale("web", &project.ServiceConfig{Labels: project.NewSliceorMap(labels)})
+		assert.NotNil(t, err, "%v", labels)
+	}
+}
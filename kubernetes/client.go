@@ -0,0 +1,27 @@
+package kubernetes
+
+import (
+	"strings"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+// DefaultHost is the kubernetes API server used when none is specified.
+const DefaultHost = "127.0.0.1:8080"
+
+// ClientOpts holds kubernetes client options.
+type ClientOpts struct {
+	Host string
+}
+
+// CreateClient creates a kubernetes client based on the specified options.
+func CreateClient(opts ClientOpts) (*client.Client, error) {
+	if opts.Host == "" {
+		opts.Host = DefaultHost
+	}
+	if !strings.Contains(opts.Host, "://") {
+		opts.Host = "http://" + opts.Host
+	}
+
+	return client.New(&client.Config{Host: opts.Host, Version: "v1"})
+}
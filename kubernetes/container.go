@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+)
+
+// Container is a project.Container implementation backed by a pod of a service.
+type Container struct {
+	service *Service
+	pod     *api.Pod
+}
+
+// NewContainer creates a container for the specified pod of the service.
+func NewContainer(service *Service, pod *api.Pod) *Container {
+	return &Container{
+		service: service,
+		pod:     pod,
+	}
+}
+
+// ID returns the pod UID.
+func (c *Container) ID() (string, error) {
+	return string(c.pod.UID), nil
+}
+
+// Name returns the pod name.
+func (c *Container) Name() string {
+	return c.pod.Name
+}
+
+// Port returns the addresses at which the specified container port (like
+// 80/tcp) is reachable through the kubernetes service: its cluster IP and, if
+// any, its node port on all nodes.
+func (c *Container) Port(port string) (string, error) {
+	number, protocol := port, "tcp"
+	if i := strings.Index(port, "/"); i >= 0 {
+		number, protocol = port[:i], port[i+1:]
+	}
+
+	target, err := strconv.Atoi(number)
+	if err != nil {
+		return "", fmt.Errorf("Invalid port %s", port)
+	}
+
+	sc, err := c.service.context.Client.Services(api.NamespaceDefault).Get(c.service.name)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	result := []string{}
+	for _, p := range sc.Spec.Ports {
+		if p.TargetPort.IntVal != target || !strings.EqualFold(string(p.Protocol), protocol) {
+			continue
+		}
+		if sc.Spec.ClusterIP != "" && sc.Spec.ClusterIP != api.ClusterIPNone {
+			result = append(result, fmt.Sprintf("%s:%d", sc.Spec.ClusterIP, p.Port))
+		}
+		if p.NodePort > 0 {
+			result = append(result, fmt.Sprintf("0.0.0.0:%d", p.NodePort))
+		}
+	}
+
+	return strings.Join(result, "\n"), nil
+}
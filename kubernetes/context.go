@@ -0,0 +1,30 @@
+package kubernetes
+
+import (
+	"github.com/docker/libcompose/project"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+)
+
+// Context holds context meta information about a libcompose project and the
+// kubernetes client used to reach the cluster.
+type Context struct {
+	project.Context
+	ClientOpts ClientOpts
+	Client     *client.Client
+}
+
+func (c *Context) open() error {
+	if c.Client != nil {
+		return nil
+	}
+
+	client, err := CreateClient(c.ClientOpts)
+	if err != nil {
+		return err
+	}
+
+	c.Client = client
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/util"
+)
+
+// Objects holds the kubernetes objects generated for a compose service.
+type Objects struct {
+	ReplicationController *api.ReplicationController
+	Service               *api.Service
+	Deployment            *extensions.Deployment
+	// Autoscale is nil unless the service is autoscaled.
+	Autoscale *Autoscale
+	// Job is nil unless the service is a job, which replaces the controllers
+	// and the service.
+	Job *JobConfig
+}
+
+// ConvertToAPI converts a service configuration to the kubernetes API objects
+// (replication controller, service and deployment) of the specified project.
+func ConvertToAPI(projectName, name string, c *project.ServiceConfig) (*Objects, error) {
+	if err := ValidateLabels(name, c); err != nil {
+		return nil, err
+	}
+
+	rcTemplate, err := ConvertToPodTemplate(projectName, name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each controller gets its own template so they can be altered independently.
+	dcTemplate, err := ConvertToPodTemplate(projectName, name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	servicePorts, err := servicePorts(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	autoscale, err := ParseAutoscale(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := ParseJob(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := 1
+	if autoscale != nil {
+		replicas = autoscale.Min
+	}
+
+	labels := ServiceLabels(projectName, name, c)
+	annotations := ServiceAnnotations(c)
+
+	rc := &api.ReplicationController{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "ReplicationController",
+			APIVersion: "v1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: api.ReplicationControllerSpec{
+			Replicas: replicas,
+			Selector: map[string]string{"service": name},
+			Template: rcTemplate,
+		},
+	}
+
+	sc := &api.Service{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: api.ServiceSpec{
+			Selector: map[string]string{"service": name},
+			Ports:    servicePorts,
+		},
+	}
+
+	dc := &extensions.Deployment{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: extensions.DeploymentSpec{
+			Replicas:       replicas,
+			Selector:       map[string]string{"service": name},
+			UniqueLabelKey: projectName,
+			Template:       dcTemplate,
+		},
+	}
+
+	return &Objects{
+		ReplicationController: rc,
+		Service:               sc,
+		Deployment:            dc,
+		Autoscale:             autoscale,
+		Job:                   job,
+	}, nil
+}
+
+// ConvertToPodTemplate converts a service configuration to the pod template
+// used by the generated controllers.
+func ConvertToPodTemplate(projectName, name string, c *project.ServiceConfig) (*api.PodTemplateSpec, error) {
+	envs, err := ParseEnvironment(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := containerPorts(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	restartPolicy, err := restartPolicy(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	readiness, liveness, err := probes(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	container := api.Container{
+		Name:           name,
+		Image:          c.Image,
+		Command:        c.Entrypoint.Slice(),
+		Args:           c.Command.Slice(),
+		Env:            envs,
+		Ports:          ports,
+		Resources:      resources(c),
+		ReadinessProbe: readiness,
+		LivenessProbe:  liveness,
+	}
+
+	if c.Privileged {
+		privileged := c.Privileged
+		container.SecurityContext = &api.SecurityContext{
+			Privileged: &privileged,
+		}
+	}
+
+	return &api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels:      ServiceLabels(projectName, name, c),
+			Annotations: ServiceAnnotations(c),
+		},
+		Spec: api.PodSpec{
+			Containers:    []api.Container{container},
+			RestartPolicy: restartPolicy,
+		},
+	}, nil
+}
+
+// ParseEnvironment returns the environment variables of a service, given as
+// NAME=VALUE or NAME:VALUE, the value of the latter being unquoted. Every
+// target parses the environment with it.
+func ParseEnvironment(name string, c *project.ServiceConfig) ([]api.EnvVar, error) {
+	var envs []api.EnvVar
+	for n, env := range c.Environment.Slice() {
+		if i := strings.Index(env, "="); i >= 0 {
+			envs = append(envs, api.EnvVar{
+				Name:  strings.TrimSpace(env[:i]),
+				Value: strings.TrimSpace(env[i+1:]),
+			})
+		} else if i := strings.Index(env, ":"); i >= 0 {
+			envs = append(envs, api.EnvVar{
+				Name:  strings.TrimSpace(env[:i]),
+				Value: strings.Trim(strings.TrimSpace(env[i+1:]), "'"),
+			})
+		} else {
+			return nil, project.NewValidationError(name, fmt.Sprintf("environment[%d]", n), "expected NAME=VALUE, got %s", env)
+		}
+	}
+	return envs, nil
+}
+
+func containerPorts(name string, c *project.ServiceConfig) ([]api.ContainerPort, error) {
+	var ports []api.ContainerPort
+	for i, port := range c.Ports {
+		target := port
+		if i := strings.Index(port, ":"); i >= 0 {
+			target = port[i+1:]
+		}
+
+		targetNumber, err := strconv.Atoi(strings.TrimSpace(target))
+		if err != nil {
+			return nil, project.NewValidationError(name, fmt.Sprintf("ports[%d]", i), "invalid container port %s", port)
+		}
+		ports = append(ports, api.ContainerPort{ContainerPort: targetNumber})
+	}
+	return ports, nil
+}
+
+func servicePorts(name string, c *project.ServiceConfig) ([]api.ServicePort, error) {
+	var servicePorts []api.ServicePort
+	for i, port := range c.Ports {
+		published, target := port, port
+		if i := strings.Index(port, ":"); i >= 0 {
+			published, target = port[:i], port[i+1:]
+		}
+		published = strings.TrimSpace(published)
+		target = strings.TrimSpace(target)
+
+		publishedNumber, err := strconv.Atoi(published)
+		if err != nil {
+			return nil, project.NewValidationError(name, fmt.Sprintf("ports[%d]", i), "invalid published port %s", port)
+		}
+		targetNumber, err := strconv.Atoi(target)
+		if err != nil {
+			return nil, project.NewValidationError(name, fmt.Sprintf("ports[%d]", i), "invalid container port %s", port)
+		}
+
+		servicePorts = append(servicePorts, api.ServicePort{
+			Port:       publishedNumber,
+			Name:       published,
+			Protocol:   api.ProtocolTCP,
+			TargetPort: util.IntOrString{IntVal: targetNumber, StrVal: target},
+		})
+	}
+	return servicePorts, nil
+}
+
+func restartPolicy(name string, c *project.ServiceConfig) (api.RestartPolicy, error) {
+	switch c.Restart {
+	case "", "always":
+		return api.RestartPolicyAlways, nil
+	case "no":
+		return api.RestartPolicyNever, nil
+	case "on-failure":
+		return api.RestartPolicyOnFailure, nil
+	}
+	return "", project.NewValidationError(name, "restart", "unknown restart policy %s", c.Restart)
+}
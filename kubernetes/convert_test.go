@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestConvertToAPI(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image:       "nginx",
+		Entrypoint:  project.NewCommand("/docker-entrypoint.sh"),
+		Command:     project.NewCommand("nginx", "-g", "daemon off;"),
+		Ports:       []string{"8080:80"},
+		Environment: project.NewMaporEqualSlice([]string{"FOO=bar"}),
+		Restart:     "on-failure",
+		Privileged:  true,
+	}
+
+	objects, err := ConvertToAPI("demo", "web", sc)
+	assert.Nil(t, err)
+
+	rc := objects.ReplicationController
+	assert.Equal(t, "web", rc.Name)
+	assert.Equal(t, "demo", rc.Labels[PROJECT.Str()])
+	assert.Equal(t, map[string]string{"service": "web"}, rc.Spec.Selector)
+
+	container := rc.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "nginx", container.Image)
+	assert.Equal(t, []string{"/docker-entrypoint.sh"}, container.Command)
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, container.Args)
+	assert.Equal(t, []api.EnvVar{{Name: "FOO", Value: "bar"}}, container.Env)
+	assert.Equal(t, []api.ContainerPort{{ContainerPort: 80}}, container.Ports)
+	assert.True(t, *container.SecurityContext.Privileged)
+	assert.Equal(t, api.RestartPolicyOnFailure, rc.Spec.Template.Spec.RestartPolicy)
+
+	port := objects.Service.Spec.Ports[0]
+	assert.Equal(t, 8080, port.Port)
+	assert.Equal(t, 80, port.TargetPort.IntVal)
+
+	// The controllers must not share their pod template.
+	assert.False(t, rc.Spec.Template == objects.Deployment.Spec.Template)
+}
+
+func TestConvertToAPIInvalid(t *testing.T) {
+	for path, sc := range map[string]*project.ServiceConfig{
+		"web.ports[1]":             {Image: "nginx", Ports: []string{"80", "http"}},
+		"web.environment[0]":       {Image: "nginx", Environment: project.NewMaporEqualSlice([]string{"FOO"})},
+		"web.restart":              {Image: "nginx", Restart: "sometimes"},
+		"web.labels[kompose.job]":  {Image: "nginx", Labels: project.NewSliceorMap(map[string]string{"kompose.job": "maybe"})},
+		"web.labels[kompose.spam]": {Image: "nginx", Labels: project.NewSliceorMap(map[string]string{"kompose.spam": "true"})},
+	} {
+		_, err := ConvertToAPI("demo", "web", sc)
+		if assert.IsType(t, &project.ValidationError{}, err, path) {
+			assert.Equal(t, path, err.(*project.ValidationError).Path())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// Init containers are not part of the API version kompose is built against,
+// clusters read them from these pod annotations (alpha up to 1.3, beta from 1.4).
+const (
+	InitContainersAlphaAnnotation = "pod.alpha.kubernetes.io/init-containers"
+	InitContainersBetaAnnotation  = "pod.beta.kubernetes.io/init-containers"
+)
+
+// WaitImage is the image of the init containers waiting for the dependencies.
+const WaitImage = "busybox"
+
+// LinkedServices returns the sorted services of the project a service links to.
+func LinkedServices(p *project.Project, name string) []string {
+	c, ok := p.Configs[name]
+	if !ok {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	result := []string{}
+	for _, link := range c.Links.Slice() {
+		linked, _ := project.NameAlias(link)
+		if _, ok := p.Configs[linked]; !ok || seen[linked] {
+			continue
+		}
+		seen[linked] = true
+		result = append(result, linked)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// DependentServices returns the sorted services of the project a service
+// depends on, through links, volumes_from or the net and ipc namespaces.
+func DependentServices(p *project.Project, name string) []string {
+	c, ok := p.Configs[name]
+	if !ok {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	result := []string{}
+	for _, rel := range project.DefaultDependentServices(p, NewService(name, c, nil)) {
+		if _, ok := p.Configs[rel.Target]; !ok || rel.Target == name || seen[rel.Target] {
+			continue
+		}
+		seen[rel.Target] = true
+		result = append(result, rel.Target)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// DependencyInitContainers returns one init container per port of the services
+// linked by a service, blocking until the kubernetes service of the dependency
+// accepts TCP connections on that port.
+func DependencyInitContainers(p *project.Project, name string) ([]api.Container, error) {
+	containers := []api.Container{}
+
+	for _, linked := range LinkedServices(p, name) {
+		ports, err := servicePorts(linked, p.Configs[linked])
+		if err != nil {
+			return nil, err
+		}
+		if len(ports) == 0 {
+			logrus.Warnf("Service %s links to %s which exposes no port, not waiting for it", name, linked)
+			continue
+		}
+
+		for _, port := range ports {
+			script := fmt.Sprintf("until nc -z %s %d; do echo waiting for %s:%d; sleep 2; done", linked, port.Port, linked, port.Port)
+			containers = append(containers, api.Container{
+				Name:    fmt.Sprintf("wait-for-%s-%d", linked, port.Port),
+				Image:   WaitImage,
+				Command: []string{"sh", "-c", script},
+			})
+		}
+	}
+
+	return containers, nil
+}
+
+// SetInitContainers sets the init containers of the pod template, through the
+// alpha and beta annotations.
+func SetInitContainers(template *api.PodTemplateSpec, containers []api.Container) error {
+	if len(containers) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(containers)
+	if err != nil {
+		return err
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[InitContainersAlphaAnnotation] = string(data)
+	template.Annotations[InitContainersBetaAnnotation] = string(data)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func newLinkedProject() *project.Project {
+	p := newTestProject()
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis", "db:database", "redis:cache", "missing"})
+	p.Configs["redis"].Ports = []string{"6379"}
+	p.Configs["db"] = &project.ServiceConfig{Image: "postgres", Ports: []string{"5432", "15432:5433"}}
+	return p
+}
+
+func TestLinkedServices(t *testing.T) {
+	p := newLinkedProject()
+	assert.Equal(t, []string{"db", "redis"}, LinkedServices(p, "web"))
+	assert.Equal(t, []string{}, LinkedServices(p, "redis"))
+}
+
+func TestDependencyInitContainers(t *testing.T) {
+	p := newLinkedProject()
+
+	containers, err := DependencyInitContainers(p, "web")
+	assert.Nil(t, err)
+	assert.Len(t, containers, 3)
+	assert.Equal(t, "wait-for-db-5432", containers[0].Name)
+	assert.Equal(t, "wait-for-db-15432", containers[1].Name)
+	assert.Equal(t, []string{"sh", "-c", "until nc -z redis 6379; do echo waiting for redis:6379; sleep 2; done"}, containers[2].Command)
+
+	template := &api.PodTemplateSpec{}
+	assert.Nil(t, SetInitContainers(template, containers))
+
+	decoded := []api.Container{}
+	assert.Nil(t, json.Unmarshal([]byte(template.Annotations[InitContainersBetaAnnotation]), &decoded))
+	assert.Equal(t, containers, decoded)
+	assert.Equal(t, template.Annotations[InitContainersBetaAnnotation], template.Annotations[InitContainersAlphaAnnotation])
+
+	template = &api.PodTemplateSpec{}
+	assert.Nil(t, SetInitContainers(template, nil))
+	assert.Nil(t, template.Annotations)
+}
+
+func TestDependentServices(t *testing.T) {
+	p := newLinkedProject()
+	p.Configs["web"].VolumesFrom = []string{"db"}
+	p.Configs["web"].Ipc = "container:cache"
+	p.Configs["cache"] = &project.ServiceConfig{Image: "memcached"}
+
+	assert.Equal(t, []string{"cache", "db", "redis"}, DependentServices(p, "web"))
+	assert.Equal(t, []string{}, DependentServices(p, "redis"))
+}
@@ -0,0 +1,721 @@
+// Package fake provides an in-memory kubernetes API server, serving the subset
+// of the v1 and extensions endpoints used by kompose, so that the kubernetes
+// commands can be tested without a cluster.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// RevisionAnnotation is set on the replica sets of the deployments, as the
+// deployment controller does.
+const RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// PodTemplateHashLabel is the default unique label key of the deployments. The
+// replication controllers of a deployment and their pods are labelled with the
+// hash of its pod template.
+const PodTemplateHashLabel = "deployment.kubernetes.io/podTemplateHash"
+
+// kinds holds the kind of the objects of the served resources.
+var kinds = map[string]string{
+	"configmaps":               "ConfigMap",
+	"cronjobs":                 "CronJob",
+	"deployments":              "Deployment",
+	"horizontalpodautoscalers": "HorizontalPodAutoscaler",
+	"ingresses":                "Ingress",
+	"jobs":                     "Job",
+	"networkpolicies":          "NetworkPolicy",
+	"persistentvolumeclaims":   "PersistentVolumeClaim",
+	"pods":                     "Pod",
+	"replicasets":              "ReplicaSet",
+	"replicationcontrollers":   "ReplicationController",
+	"secrets":                  "Secret",
+	"services":                 "Service",
+}
+
+// object is a kubernetes object as decoded from JSON.
+type object map[string]interface{}
+
+// Server is a kubernetes API server keeping its objects in memory. The
+// replication controllers and jobs get their pods created, running and ready,
+// as soon as they are written. As with the 1.1 deployment controller, the deployments
+// get a replication controller per pod template, labelled with the template
+// labels and its hash, and keep their revision history in replica sets.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// objects holds the objects by resource, then by namespace/name.
+	objects map[string]map[string]object
+	// owners holds the controller or job of the pods, and the deployment of
+	// the replication controllers and replica sets.
+	owners   map[string]string
+	logs     map[string]string
+	requests []string
+	counter  int
+}
+
+// NewServer starts a server, which must be closed once done.
+func NewServer() *Server {
+	s := &Server{
+		objects: map[string]map[string]object{},
+		owners:  map[string]string{},
+		logs:    map[string]string{},
+	}
+	for resource := range kinds {
+		s.objects[resource] = map[string]object{}
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Client returns a client of the server.
+func (s *Server) Client() *client.Client {
+	c, err := client.New(&client.Config{Host: s.URL, Version: "v1"})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create the client of the fake server: %v", err))
+	}
+	return c
+}
+
+// Create adds an object of the resource to the default namespace, as if it was
+// posted to the server.
+func (s *Server) Create(resource string, v interface{}) error {
+	obj, err := toObject(v)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := kinds[resource]; !ok {
+		return fmt.Errorf("Unknown resource %s", resource)
+	}
+	_, err = s.create(resource, "default", obj)
+	return err
+}
+
+// Get decodes the object of the resource with the specified name, from the
+// default namespace, into v.
+func (s *Server) Get(resource, name string, v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[resource]["default/"+name]
+	if !ok {
+		return fmt.Errorf("%s %s not found", resource, name)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Names returns the sorted names of the objects of the resource, from the
+// default namespace.
+func (s *Server) Names(resource string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := []string{}
+	for _, key := range s.keys(resource) {
+		names = append(names, strings.TrimPrefix(key, "default/"))
+	}
+	return names
+}
+
+// SetLog sets the logs returned for the containers of a pod.
+func (s *Server) SetLog(pod, log string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs["default/"+pod] = log
+}
+
+// Requests returns the requests served so far, as "METHOD path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+// request is the target of an API request.
+type request struct {
+	apiVersion  string
+	namespace   string
+	resource    string
+	name        string
+	subresource string
+}
+
+// parsePath parses /api/v1/namespaces/NS/RESOURCE[/NAME[/SUB]] and
+// /apis/GROUP/VERSION/namespaces/NS/RESOURCE[/NAME[/SUB]].
+func parsePath(path string) (*request, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	req := &request{}
+	switch {
+	case len(parts) > 2 && parts[0] == "api":
+		req.apiVersion, parts = parts[1], parts[2:]
+	case len(parts) > 3 && parts[0] == "apis":
+		req.apiVersion, parts = parts[1]+"/"+parts[2], parts[3:]
+	default:
+		return nil, fmt.Errorf("the server could not find the requested resource %s", path)
+	}
+
+	if len(parts) < 3 || len(parts) > 5 || parts[0] != "namespaces" {
+		return nil, fmt.Errorf("the server could not find the requested resource %s", path)
+	}
+	req.namespace, req.resource = parts[1], parts[2]
+	if len(parts) > 3 {
+		req.name = parts[3]
+	}
+	if len(parts) > 4 {
+		req.subresource = parts[4]
+	}
+
+	if _, ok := kinds[req.resource]; !ok {
+		return nil, fmt.Errorf("the server could not find the requested resource %s", req.resource)
+	}
+	return req, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	req, err := parsePath(r.URL.Path)
+	if err != nil {
+		writeStatus(w, http.StatusNotFound, "NotFound", err.Error())
+		return
+	}
+
+	var body object
+	if r.Method == "POST" || r.Method == "PUT" {
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(data, &body)
+		}
+		if err != nil {
+			writeStatus(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("invalid body: %v", err))
+			return
+		}
+	}
+
+	key := req.namespace + "/" + req.name
+	current, exists := s.objects[req.resource][key]
+	if req.name != "" && !exists && !(r.Method == "POST" && req.subresource == "") {
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("%s %q not found", req.resource, req.name))
+		return
+	}
+
+	switch {
+	case r.Method == "GET" && req.name == "":
+		s.list(w, r, req)
+	case r.Method == "GET" && req.subresource == "":
+		writeJSON(w, http.StatusOK, current)
+	case r.Method == "GET" && req.subresource == "scale":
+		s.scale(w, req, current)
+	case r.Method == "GET" && req.subresource == "log" && req.resource == "pods":
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(s.logs[key]))
+	case r.Method == "POST" && req.name == "":
+		obj, err := s.create(req.resource, req.namespace, body)
+		if err != nil {
+			writeStatus(w, http.StatusConflict, "AlreadyExists", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, obj)
+	case r.Method == "POST" && req.subresource == "rollback" && req.resource == "deployments":
+		if err := s.rollback(key, body); err != nil {
+			writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+			return
+		}
+		writeStatus(w, http.StatusOK, "", "")
+	case r.Method == "PUT" && req.subresource == "":
+		writeJSON(w, http.StatusOK, s.update(req.resource, key, body))
+	case r.Method == "PUT" && req.subresource == "scale":
+		replicas, _ := get(body, "spec")["replicas"].(float64)
+		get(current, "spec")["replicas"] = int(replicas)
+		s.update(req.resource, key, current)
+		s.scale(w, req, s.objects[req.resource][key])
+	case r.Method == "DELETE" && req.name != "" && req.subresource == "":
+		owner := s.owners[req.resource+"/"+key]
+		s.remove(req.resource, key)
+		// The controller of a deleted pod replaces it.
+		if parts := strings.SplitN(owner, "/", 2); len(parts) == 2 {
+			if _, ok := s.objects[parts[0]][parts[1]]; ok {
+				s.reconcile(parts[0], parts[1])
+			}
+		}
+		writeStatus(w, http.StatusOK, "", "")
+	default:
+		writeStatus(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+	}
+}
+
+// list writes the objects of the resource matching the labelSelector query
+// parameter, sorted by name.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, req *request) {
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	items := []object{}
+	for _, key := range s.keys(req.resource) {
+		obj := s.objects[req.resource][key]
+		if !strings.HasPrefix(key, req.namespace+"/") || !selector.Matches(labels.Set(stringMap(get(obj, "metadata")["labels"]))) {
+			continue
+		}
+		items = append(items, obj)
+	}
+
+	writeJSON(w, http.StatusOK, object{
+		"kind":       kinds[req.resource] + "List",
+		"apiVersion": req.apiVersion,
+		"metadata":   object{"resourceVersion": strconv.Itoa(s.counter)},
+		"items":      items,
+	})
+}
+
+// scale writes the scale subresource of a replication controller or deployment.
+func (s *Server) scale(w http.ResponseWriter, req *request, obj object) {
+	if req.resource != "replicationcontrollers" && req.resource != "deployments" {
+		writeStatus(w, http.StatusNotFound, "NotFound", fmt.Sprintf("%s have no scale", req.resource))
+		return
+	}
+
+	spec := get(obj, "spec")
+	writeJSON(w, http.StatusOK, object{
+		"kind":       "Scale",
+		"apiVersion": "extensions/v1beta1",
+		"metadata": object{
+			"name":            req.name,
+			"namespace":       req.namespace,
+			"resourceVersion": get(obj, "metadata")["resourceVersion"],
+		},
+		"spec":   object{"replicas": replicas(spec)},
+		"status": object{"replicas": get(obj, "status")["replicas"], "selector": spec["selector"]},
+	})
+}
+
+// keys returns the sorted keys of the objects of the resource.
+func (s *Server) keys(resource string) []string {
+	keys := []string{}
+	for key := range s.objects[resource] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) next() int {
+	s.counter++
+	return s.counter
+}
+
+// create stores a new object, failing if one with the same name exists.
+func (s *Server) create(resource, namespace string, obj object) (object, error) {
+	metadata := get(obj, "metadata")
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		name = fmt.Sprintf("%s%d", metadata["generateName"], s.next())
+	}
+	key := namespace + "/" + name
+	if _, ok := s.objects[resource][key]; ok {
+		return nil, fmt.Errorf("%s %q already exists", resource, name)
+	}
+
+	metadata["name"] = name
+	metadata["namespace"] = namespace
+	metadata["uid"] = fmt.Sprintf("%s-%d", name, s.next())
+	metadata["creationTimestamp"] = time.Now().UTC().Format(time.RFC3339)
+	metadata["resourceVersion"] = strconv.Itoa(s.next())
+	if _, ok := obj["kind"]; !ok {
+		obj["kind"] = kinds[resource]
+	}
+	if resource == "services" {
+		spec := get(obj, "spec")
+		if ip, _ := spec["clusterIP"].(string); ip == "" {
+			spec["clusterIP"] = fmt.Sprintf("10.0.0.%d", len(s.objects[resource])+1)
+		}
+	}
+
+	s.objects[resource][key] = obj
+	s.reconcile(resource, key)
+	return obj, nil
+}
+
+// update replaces a stored object, keeping its identity.
+func (s *Server) update(resource, key string, obj object) object {
+	previous := get(s.objects[resource][key], "metadata")
+	metadata := get(obj, "metadata")
+	for _, field := range []string{"name", "namespace", "uid", "creationTimestamp"} {
+		metadata[field] = previous[field]
+	}
+	metadata["resourceVersion"] = strconv.Itoa(s.next())
+
+	s.objects[resource][key] = obj
+	s.reconcile(resource, key)
+	return obj
+}
+
+// remove deletes an object. As with a real server, the pods of a deleted
+// controller are left running.
+func (s *Server) remove(resource, key string) {
+	delete(s.objects[resource], key)
+	delete(s.owners, resource+"/"+key)
+	if resource == "pods" {
+		delete(s.logs, key)
+	}
+}
+
+// reconcile plays the part of the controllers of replication controllers,
+// deployments and jobs.
+func (s *Server) reconcile(resource, key string) {
+	switch resource {
+	case "replicationcontrollers":
+		obj := s.objects[resource][key]
+		desired := replicas(get(obj, "spec"))
+		s.reconcilePods(resource, key, desired)
+		get(obj, "status")["replicas"] = desired
+	case "deployments":
+		s.reconcileDeployment(key)
+	case "jobs":
+		// The pods of a job keep running, they never complete.
+		obj := s.objects[resource][key]
+		desired := parallelism(get(obj, "spec"))
+		s.reconcilePods(resource, key, desired)
+		get(obj, "status")["active"] = desired
+	}
+}
+
+// reconcilePods replaces the pods of a controller of another template, and
+// adds or removes pods to match the desired number.
+func (s *Server) reconcilePods(resource, key string, desired int) {
+	obj := s.objects[resource][key]
+	spec := get(obj, "spec")
+	template := get(spec, "template")
+	templateLabels := stringMap(get(template, "metadata")["labels"])
+	owner := resource + "/" + key
+
+	count := 0
+	for _, podKey := range s.keys("pods") {
+		if s.owners["pods/"+podKey] != owner {
+			continue
+		}
+		pod := s.objects["pods"][podKey]
+		if count >= desired || !reflect.DeepEqual(stringMap(get(pod, "metadata")["labels"]), templateLabels) {
+			s.remove("pods", podKey)
+			continue
+		}
+		count++
+	}
+
+	namespace := get(obj, "metadata")["namespace"].(string)
+	for ; count < desired; count++ {
+		pod := newPod(get(obj, "metadata")["name"].(string), template)
+		created, _ := s.create("pods", namespace, pod)
+		s.owners["pods/"+namespace+"/"+get(created, "metadata")["name"].(string)] = owner
+	}
+}
+
+// reconcileDeployment scales the replication controller of the current
+// template of a deployment to its replicas, creating it if needed, and the
+// replication controllers of the other templates down to zero.
+func (s *Server) reconcileDeployment(key string) {
+	obj := s.objects["deployments"][key]
+	metadata := get(obj, "metadata")
+	namespace := metadata["namespace"].(string)
+	spec := get(obj, "spec")
+	desired := replicas(spec)
+	template := get(spec, "template")
+	owner := "deployments/" + key
+
+	hash := templateHash(template)
+	name := fmt.Sprintf("%s-%s", metadata["name"], hash)
+	for _, rcKey := range s.keys("replicationcontrollers") {
+		if s.owners["replicationcontrollers/"+rcKey] != owner || rcKey == namespace+"/"+name {
+			continue
+		}
+		rc := s.objects["replicationcontrollers"][rcKey]
+		get(rc, "spec")["replicas"] = 0
+		s.update("replicationcontrollers", rcKey, rc)
+	}
+
+	labels := stringMap(get(template, "metadata")["labels"])
+	labels[PodTemplateHashLabel] = hash
+	selector := stringMap(spec["selector"])
+	selector[PodTemplateHashLabel] = hash
+	rcTemplate := clone(template)
+	get(rcTemplate, "metadata")["labels"] = labels
+
+	rc := object{
+		"kind":       "ReplicationController",
+		"apiVersion": "v1",
+		"metadata":   object{"name": name, "labels": labels},
+		"spec":       object{"replicas": desired, "selector": selector, "template": rcTemplate},
+	}
+	rcKey := namespace + "/" + name
+	if _, ok := s.objects["replicationcontrollers"][rcKey]; ok {
+		s.update("replicationcontrollers", rcKey, rc)
+	} else {
+		s.owners["replicationcontrollers/"+rcKey] = owner
+		s.create("replicationcontrollers", namespace, rc)
+	}
+
+	status := get(obj, "status")
+	status["replicas"] = desired
+	status["updatedReplicas"] = desired
+	status["availableReplicas"] = desired
+	s.recordRevision(key, obj, template, desired)
+}
+
+// templateHash returns the hash of a pod template, as the value of the pod
+// template hash label.
+func templateHash(template object) string {
+	data, _ := json.Marshal(template)
+	return strconv.FormatUint(uint64(adler32.Checksum(data)), 10)
+}
+
+// newPod returns a running and ready pod of the template.
+func newPod(controller string, template object) object {
+	pod := clone(template)
+	metadata := get(pod, "metadata")
+	metadata["generateName"] = controller + "-"
+	spec := get(pod, "spec")
+
+	statuses := []interface{}{}
+	containers, _ := spec["containers"].([]interface{})
+	for _, c := range containers {
+		container, _ := c.(map[string]interface{})
+		statuses = append(statuses, object{
+			"name":         container["name"],
+			"image":        container["image"],
+			"ready":        true,
+			"restartCount": 0,
+			"state":        object{"running": object{"startedAt": time.Now().UTC().Format(time.RFC3339)}},
+		})
+	}
+
+	return object{
+		"kind":       "Pod",
+		"apiVersion": "v1",
+		"metadata":   metadata,
+		"spec":       spec,
+		"status": object{
+			"phase":             "Running",
+			"conditions":        []interface{}{object{"type": "Ready", "status": "True"}},
+			"containerStatuses": statuses,
+		},
+	}
+}
+
+// recordRevision keeps a replica set per template of a deployment. A template
+// seen before, after a rollback, gets its replica set back with a new revision.
+func (s *Server) recordRevision(key string, deployment, template object, desired int) {
+	owner := "deployments/" + key
+	var current object
+	revision := 0
+	for _, rsKey := range s.keys("replicasets") {
+		if s.owners["replicasets/"+rsKey] != owner {
+			continue
+		}
+		rs := s.objects["replicasets"][rsKey]
+		if number := revisionOf(rs); number > revision {
+			revision = number
+		}
+		get(rs, "spec")["replicas"] = 0
+		if sameJSON(get(get(rs, "spec"), "template"), template) {
+			current = rs
+		}
+	}
+
+	if current == nil || revisionOf(current) != revision {
+		revision++
+	}
+	if current == nil {
+		metadata := get(deployment, "metadata")
+		namespace := metadata["namespace"].(string)
+		rs := object{
+			"kind":       "ReplicaSet",
+			"apiVersion": "extensions/v1beta1",
+			"metadata": object{
+				"name":   fmt.Sprintf("%s-%d", metadata["name"], s.next()),
+				"labels": stringMap(get(template, "metadata")["labels"]),
+			},
+			"spec": object{"template": clone(template)},
+		}
+		created, _ := s.create("replicasets", namespace, rs)
+		s.owners["replicasets/"+namespace+"/"+get(created, "metadata")["name"].(string)] = owner
+		current = created
+	}
+
+	get(current, "metadata")["annotations"] = object{RevisionAnnotation: strconv.Itoa(revision)}
+	get(current, "spec")["replicas"] = desired
+	get(deployment, "metadata")["annotations"] = mergeAnnotation(get(deployment, "metadata")["annotations"], strconv.Itoa(revision))
+}
+
+// rollback sets the template of a deployment back to the one of a revision,
+// the previous one if the revision is 0.
+func (s *Server) rollback(key string, body object) error {
+	owner := "deployments/" + key
+	target, _ := get(body, "rollbackTo")["revision"].(float64)
+
+	revisions := map[int]object{}
+	numbers := []int{}
+	for _, rsKey := range s.keys("replicasets") {
+		if s.owners["replicasets/"+rsKey] == owner {
+			rs := s.objects["replicasets"][rsKey]
+			revisions[revisionOf(rs)] = rs
+			numbers = append(numbers, revisionOf(rs))
+		}
+	}
+	sort.Ints(numbers)
+
+	revision := int(target)
+	if revision == 0 && len(numbers) > 1 {
+		revision = numbers[len(numbers)-2]
+	}
+	rs, ok := revisions[revision]
+	if !ok {
+		return fmt.Errorf("unable to find revision %d", revision)
+	}
+
+	deployment := s.objects["deployments"][key]
+	get(deployment, "spec")["template"] = clone(get(get(rs, "spec"), "template"))
+	s.update("deployments", key, deployment)
+	return nil
+}
+
+func revisionOf(rs object) int {
+	annotations := stringMap(get(rs, "metadata")["annotations"])
+	number, _ := strconv.Atoi(annotations[RevisionAnnotation])
+	return number
+}
+
+func mergeAnnotation(annotations interface{}, revision string) object {
+	result := object{}
+	for k, v := range stringMap(annotations) {
+		result[k] = v
+	}
+	result[RevisionAnnotation] = revision
+	return result
+}
+
+// replicas returns the replicas of a controller spec, 1 if not set.
+func replicas(spec object) int {
+	if value, ok := spec["replicas"].(float64); ok {
+		return int(value)
+	}
+	if value, ok := spec["replicas"].(int); ok {
+		return value
+	}
+	return 1
+}
+
+// parallelism returns the number of pods a job runs at once.
+func parallelism(spec object) int {
+	if value, ok := spec["parallelism"].(float64); ok {
+		return int(value)
+	}
+	return 1
+}
+
+// get returns the field of an object holding an object, adding it if missing.
+func get(obj object, field string) object {
+	switch value := obj[field].(type) {
+	case object:
+		return value
+	case map[string]interface{}:
+		obj[field] = object(value)
+		return value
+	}
+	value := object{}
+	if obj != nil {
+		obj[field] = value
+	}
+	return value
+}
+
+func stringMap(v interface{}) map[string]string {
+	result := map[string]string{}
+	switch m := v.(type) {
+	case map[string]string:
+		for k, value := range m {
+			result[k] = value
+		}
+	case object:
+		for k, value := range m {
+			result[k] = fmt.Sprint(value)
+		}
+	case map[string]interface{}:
+		for k, value := range m {
+			result[k] = fmt.Sprint(value)
+		}
+	}
+	return result
+}
+
+// sameJSON reports whether two values have the same JSON encoding.
+func sameJSON(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(dataA) == string(dataB)
+}
+
+func toObject(v interface{}) (object, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	obj := object{}
+	return obj, json.Unmarshal(data, &obj)
+}
+
+func clone(obj object) object {
+	result, _ := toObject(obj)
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeStatus writes a Status, a failure unless the reason is empty, which the
+// client turns into an API error.
+func writeStatus(w http.ResponseWriter, code int, reason, message string) {
+	status := "Success"
+	if reason != "" {
+		status = "Failure"
+	}
+	writeJSON(w, code, object{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"metadata":   object{},
+		"status":     status,
+		"message":    message,
+		"reason":     reason,
+		"code":       code,
+	})
+}
@@ -0,0 +1,177 @@
+package fake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+func testTemplate(image string) *api.PodTemplateSpec {
+	return &api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{Labels: map[string]string{"service": "web", "image": image}},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "web", Image: image}},
+		},
+	}
+}
+
+func TestReplicationController(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+
+	rc := &api.ReplicationController{
+		ObjectMeta: api.ObjectMeta{Name: "web", Labels: map[string]string{"service": "web"}},
+		Spec: api.ReplicationControllerSpec{
+			Replicas: 2,
+			Selector: map[string]string{"service": "web"},
+			Template: testTemplate("nginx"),
+		},
+	}
+	created, err := client.ReplicationControllers(api.NamespaceDefault).Create(rc)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, created.Status.Replicas)
+
+	_, err = client.ReplicationControllers(api.NamespaceDefault).Create(rc)
+	assert.True(t, errors.IsAlreadyExists(err), "%v", err)
+
+	pods, err := client.Pods(api.NamespaceDefault).List(labels.SelectorFromSet(labels.Set{"service": "web"}), fields.Everything())
+	assert.Nil(t, err)
+	assert.Len(t, pods.Items, 2)
+	for i := range pods.Items {
+		assert.True(t, api.IsPodReady(&pods.Items[i]))
+		assert.Equal(t, "nginx", pods.Items[i].Labels["image"])
+	}
+
+	pods, err = client.Pods(api.NamespaceDefault).List(labels.SelectorFromSet(labels.Set{"service": "db"}), fields.Everything())
+	assert.Nil(t, err)
+	assert.Len(t, pods.Items, 0)
+
+	scales := client.Extensions().Scales(api.NamespaceDefault)
+	scale, err := scales.Get("ReplicationController", "web")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, scale.Spec.Replicas)
+	scale.Spec.Replicas = 1
+	_, err = scales.Update("ReplicationController", scale)
+	assert.Nil(t, err)
+	assert.Len(t, s.Names("pods"), 1)
+
+	// A new template replaces the pods.
+	created.Spec.Replicas = 1
+	created.Spec.Template = testTemplate("nginx:1.9")
+	_, err = client.ReplicationControllers(api.NamespaceDefault).Update(created)
+	assert.Nil(t, err)
+	pod := api.Pod{}
+	names := s.Names("pods")
+	assert.Len(t, names, 1)
+	assert.Nil(t, s.Get("pods", names[0], &pod))
+	assert.Equal(t, "nginx:1.9", pod.Spec.Containers[0].Image)
+
+	assert.Nil(t, client.ReplicationControllers(api.NamespaceDefault).Delete("web"))
+	_, err = client.ReplicationControllers(api.NamespaceDefault).Get("web")
+	assert.True(t, errors.IsNotFound(err), "%v", err)
+}
+
+func TestServiceClusterIP(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	assert.Nil(t, s.Create("services", &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "web"},
+		Spec:       api.ServiceSpec{Ports: []api.ServicePort{{Port: 80}}},
+	}))
+
+	service, err := s.Client().Services(api.NamespaceDefault).Get("web")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, service.Spec.ClusterIP)
+}
+
+func TestDeploymentRevisions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	deployments := s.Client().Extensions().Deployments(api.NamespaceDefault)
+
+	dc := &extensions.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "web"},
+		Spec: extensions.DeploymentSpec{
+			Replicas: 3,
+			Selector: map[string]string{"service": "web"},
+			Template: testTemplate("nginx"),
+		},
+	}
+	dc, err := deployments.Create(dc)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, dc.Status.UpdatedReplicas)
+
+	// The pods belong to a replication controller of the template.
+	rcs := s.Names("replicationcontrollers")
+	assert.Len(t, rcs, 1)
+	rc := api.ReplicationController{}
+	assert.Nil(t, s.Get("replicationcontrollers", rcs[0], &rc))
+	assert.Equal(t, 3, rc.Spec.Replicas)
+	assert.Equal(t, "web", rc.Labels["service"])
+	assert.NotEmpty(t, rc.Labels[PodTemplateHashLabel])
+	assert.Equal(t, rc.Labels[PodTemplateHashLabel], rc.Spec.Selector[PodTemplateHashLabel])
+
+	dc.Spec.Template = testTemplate("nginx:1.9")
+	dc, err = deployments.Update(dc)
+	assert.Nil(t, err)
+	assert.Equal(t, "2", dc.Annotations[RevisionAnnotation])
+	assert.Len(t, s.Names("replicasets"), 2)
+	assert.Len(t, s.Names("pods"), 3)
+
+	// The controller of the previous template is scaled down.
+	assert.Len(t, s.Names("replicationcontrollers"), 2)
+	assert.Nil(t, s.Get("replicationcontrollers", rcs[0], &rc))
+	assert.Equal(t, 0, rc.Spec.Replicas)
+
+	err = s.Client().ExtensionsClient.Post().Namespace(api.NamespaceDefault).Resource("deployments").Name("web").SubResource("rollback").
+		Body([]byte(`{"kind":"DeploymentRollback","apiVersion":"extensions/v1beta1","name":"web","rollbackTo":{"revision":0}}`)).Do().Error()
+	assert.Nil(t, err)
+
+	dc, err = deployments.Get("web")
+	assert.Nil(t, err)
+	assert.Equal(t, "nginx", dc.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "3", dc.Annotations[RevisionAnnotation])
+	// The replica set of the first revision is reused.
+	assert.Len(t, s.Names("replicasets"), 2)
+}
+
+func TestJob(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	client := s.Client()
+
+	parallelism := 2
+	assert.Nil(t, s.Create("jobs", &extensions.Job{
+		ObjectMeta: api.ObjectMeta{Name: "migrate"},
+		Spec:       extensions.JobSpec{Parallelism: &parallelism, Template: *testTemplate("nginx")},
+	}))
+	pods := s.Names("pods")
+	assert.Len(t, pods, 2)
+
+	// The job replaces its deleted pods, until it is deleted itself.
+	assert.Nil(t, client.Pods(api.NamespaceDefault).Delete(pods[0], nil))
+	assert.Len(t, s.Names("pods"), 2)
+	assert.NotContains(t, s.Names("pods"), pods[0])
+
+	assert.Nil(t, client.Extensions().Jobs(api.NamespaceDefault).Delete("migrate", nil))
+	pods = s.Names("pods")
+	assert.Nil(t, client.Pods(api.NamespaceDefault).Delete(pods[0], nil))
+	assert.Len(t, s.Names("pods"), 1)
+}
+
+func TestUnknownResource(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	_, err := s.Client().RESTClient.Get().Namespace(api.NamespaceDefault).Resource("widgets").Do().Raw()
+	assert.True(t, errors.IsNotFound(err), "%v", err)
+	assert.Equal(t, []string{"GET /api/v1/namespaces/default/widgets"}, s.Requests())
+}
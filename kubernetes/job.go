@@ -0,0 +1,267 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// Job labels turn a one-shot service into a job, or into a cron job run on the
+// schedule, in the cron format, of the schedule label. The other labels set the
+// number of successful runs, how many run at once, how many failed runs are
+// retried and how long, in seconds, the job may run.
+const (
+	JobLabel             = "kompose.job"
+	ScheduleLabel        = "kompose.schedule"
+	JobCompletionsLabel  = "kompose.job.completions"
+	JobParallelismLabel  = "kompose.job.parallelism"
+	JobBackoffLimitLabel = "kompose.job.backoff-limit"
+	JobDeadlineLabel     = "kompose.job.deadline"
+)
+
+func init() {
+	RegisterDirective(JobLabel, ScheduleLabel, JobCompletionsLabel, JobParallelismLabel, JobBackoffLimitLabel, JobDeadlineLabel)
+}
+
+// Jobs of the API version kompose is built against have no backoff limit and
+// cron jobs are not part of it, the following types mirror the batch ones.
+
+// Job runs pods until the specified number of them completed successfully.
+type Job struct {
+	unversioned.TypeMeta `json:",inline"`
+	api.ObjectMeta       `json:"metadata,omitempty"`
+	Spec                 JobSpec `json:"spec"`
+}
+
+// JobSpec describes the pods of a job and how many of them must succeed.
+type JobSpec struct {
+	Parallelism           *int                `json:"parallelism,omitempty"`
+	Completions           *int                `json:"completions,omitempty"`
+	ActiveDeadlineSeconds *int64              `json:"activeDeadlineSeconds,omitempty"`
+	BackoffLimit          *int                `json:"backoffLimit,omitempty"`
+	Template              api.PodTemplateSpec `json:"template"`
+}
+
+// CronJob creates a job on a schedule.
+type CronJob struct {
+	unversioned.TypeMeta `json:",inline"`
+	api.ObjectMeta       `json:"metadata,omitempty"`
+	Spec                 CronJobSpec `json:"spec"`
+}
+
+// CronJobSpec holds the schedule of a cron job and the job it creates.
+type CronJobSpec struct {
+	Schedule    string          `json:"schedule"`
+	JobTemplate JobTemplateSpec `json:"jobTemplate"`
+}
+
+// JobTemplateSpec describes the jobs created by a cron job.
+type JobTemplateSpec struct {
+	api.ObjectMeta `json:"metadata,omitempty"`
+	Spec           JobSpec `json:"spec"`
+}
+
+// JobConfig holds the job configuration of a service.
+type JobConfig struct {
+	// Schedule is empty unless the job is a cron job.
+	Schedule     string
+	Completions  *int
+	Parallelism  *int
+	BackoffLimit *int
+	Deadline     *int64
+}
+
+// ParseJob returns the job configuration set by the labels of a service, or
+// nil if it is not a job.
+func ParseJob(name string, c *project.ServiceConfig) (*JobConfig, error) {
+	labels := c.Labels.MapParts()
+
+	isJob := false
+	if value, ok := labels[JobLabel]; ok {
+		var err error
+		if isJob, err = strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return nil, project.NewValidationError(name, project.LabelField(JobLabel), "must be a boolean, got %s", value)
+		}
+	}
+
+	schedule, scheduled := labels[ScheduleLabel]
+	if !isJob && !scheduled {
+		for _, label := range []string{JobCompletionsLabel, JobParallelismLabel, JobBackoffLimitLabel, JobDeadlineLabel} {
+			if _, ok := labels[label]; ok {
+				return nil, project.NewValidationError(name, project.LabelField(label), "the service is not a job, set %s or %s", JobLabel, ScheduleLabel)
+			}
+		}
+		return nil, nil
+	}
+
+	if _, ok := labels[AutoscaleMaxLabel]; ok {
+		return nil, project.NewValidationError(name, project.LabelField(AutoscaleMaxLabel), "the service is a job and cannot be autoscaled")
+	}
+
+	job := &JobConfig{}
+	if scheduled {
+		job.Schedule = strings.TrimSpace(schedule)
+		if err := validateSchedule(job.Schedule); err != nil {
+			return nil, project.NewValidationError(name, project.LabelField(ScheduleLabel), "%v", err)
+		}
+	}
+
+	var err error
+	if job.Completions, err = jobLabel(name, labels, JobCompletionsLabel, 1); err != nil {
+		return nil, err
+	}
+	if job.Parallelism, err = jobLabel(name, labels, JobParallelismLabel, 1); err != nil {
+		return nil, err
+	}
+	if job.BackoffLimit, err = jobLabel(name, labels, JobBackoffLimitLabel, 0); err != nil {
+		return nil, err
+	}
+	deadline, err := jobLabel(name, labels, JobDeadlineLabel, 1)
+	if err != nil {
+		return nil, err
+	}
+	if deadline != nil {
+		seconds := int64(*deadline)
+		job.Deadline = &seconds
+	}
+
+	return job, nil
+}
+
+// jobLabel returns the value of a numeric job label, nil if it is not set.
+func jobLabel(name string, labels map[string]string, label string, min int) (*int, error) {
+	value, ok := labels[label]
+	if !ok {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || number < min {
+		return nil, project.NewValidationError(name, project.LabelField(label), "must be a number of at least %d, got %s", min, value)
+	}
+	return &number, nil
+}
+
+// validateSchedule checks a cron schedule has five fields, or is one of the
+// predefined schedules.
+func validateSchedule(schedule string) error {
+	if strings.HasPrefix(schedule, "@") {
+		switch schedule {
+		case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly":
+			return nil
+		}
+		return fmt.Errorf("unknown schedule %s", schedule)
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %q", schedule)
+	}
+	for _, field := range fields {
+		if strings.Trim(field, "0123456789*/,-?") != "" && !isName(field) {
+			return fmt.Errorf("invalid field %s in %q", field, schedule)
+		}
+	}
+	return nil
+}
+
+// isName reports whether a cron field is a list of month or day names, like
+// JAN-MAR or MON,WED.
+func isName(field string) bool {
+	for _, part := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' || r == '-' }) {
+		if len(part) != 3 || strings.Trim(strings.ToUpper(part), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// jobRestartPolicy returns the restart policy of the pods of a job, which
+// cannot always restart since they must terminate.
+func jobRestartPolicy(name string, c *project.ServiceConfig) (api.RestartPolicy, error) {
+	switch c.Restart {
+	case "", "on-failure":
+		return api.RestartPolicyOnFailure, nil
+	case "no":
+		return api.RestartPolicyNever, nil
+	}
+	return "", project.NewValidationError(name, "restart", "%s is not supported by jobs, use no or on-failure", c.Restart)
+}
+
+// ConvertToJob converts a service configuration to a job running its
+// container until it succeeds.
+func ConvertToJob(projectName, name string, c *project.ServiceConfig, job *JobConfig) (*Job, error) {
+	spec, err := jobSpec(projectName, name, c, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Labels:      ServiceLabels(projectName, name, c),
+			Annotations: ServiceAnnotations(c),
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// ConvertToCronJob converts a service configuration to a cron job creating a
+// job on the schedule of the service.
+func ConvertToCronJob(projectName, name string, c *project.ServiceConfig, job *JobConfig) (*CronJob, error) {
+	spec, err := jobSpec(projectName, name, c, job)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := ServiceLabels(projectName, name, c)
+	annotations := ServiceAnnotations(c)
+	return &CronJob{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "CronJob",
+			APIVersion: "batch/v2alpha1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: CronJobSpec{
+			Schedule: job.Schedule,
+			JobTemplate: JobTemplateSpec{
+				ObjectMeta: api.ObjectMeta{
+					Labels:      labels,
+					Annotations: annotations,
+				},
+				Spec: *spec,
+			},
+		},
+	}, nil
+}
+
+func jobSpec(projectName, name string, c *project.ServiceConfig, job *JobConfig) (*JobSpec, error) {
+	template, err := ConvertToPodTemplate(projectName, name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.Spec.RestartPolicy, err = jobRestartPolicy(name, c); err != nil {
+		return nil, err
+	}
+
+	return &JobSpec{
+		Parallelism:           job.Parallelism,
+		Completions:           job.Completions,
+		ActiveDeadlineSeconds: job.Deadline,
+		BackoffLimit:          job.BackoffLimit,
+		Template:              *template,
+	}, nil
+}
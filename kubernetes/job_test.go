@@ -0,0 +1,91 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestConvertToJob(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image:   "web",
+		Restart: "no",
+		Labels: project.NewSliceorMap(map[string]string{
+			JobLabel:             "true",
+			JobCompletionsLabel:  "3",
+			JobParallelismLabel:  "2",
+			JobBackoffLimitLabel: "0",
+			JobDeadlineLabel:     "600",
+		}),
+	}
+
+	objects, err := ConvertToAPI("demo", "migrate", sc)
+	assert.Nil(t, err)
+	assert.NotNil(t, objects.Job)
+
+	job, err := ConvertToJob("demo", "migrate", sc, objects.Job)
+	assert.Nil(t, err)
+	assert.Equal(t, "Job", job.Kind)
+	assert.Equal(t, "batch/v1", job.APIVersion)
+	assert.Equal(t, "demo", job.Labels[PROJECT.Str()])
+	assert.Equal(t, 3, *job.Spec.Completions)
+	assert.Equal(t, 2, *job.Spec.Parallelism)
+	assert.Equal(t, 0, *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(600), *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, api.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	assert.Equal(t, "web", job.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestConvertToCronJob(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image:  "web",
+		Labels: project.NewSliceorMap(map[string]string{ScheduleLabel: "0 3 * * MON-FRI"}),
+	}
+
+	config, err := ParseJob("report", sc)
+	assert.Nil(t, err)
+
+	cronJob, err := ConvertToCronJob("demo", "report", sc, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "CronJob", cronJob.Kind)
+	assert.Equal(t, "0 3 * * MON-FRI", cronJob.Spec.Schedule)
+	assert.Nil(t, cronJob.Spec.JobTemplate.Spec.Completions)
+	assert.Equal(t, api.RestartPolicyOnFailure, cronJob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy)
+}
+
+func TestParseJob(t *testing.T) {
+	for _, labels := range []map[string]string{
+		{},
+		{JobLabel: "false"},
+	} {
+		job, err := ParseJob("web", &project.ServiceConfig{Labels: project.NewSliceorMap(labels)})
+		assert.Nil(t, err)
+		assert.Nil(t, job)
+	}
+
+	job, err := ParseJob("web", &project.ServiceConfig{
+		Labels: project.NewSliceorMap(map[string]string{ScheduleLabel: "@daily"}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &JobConfig{Schedule: "@daily"}, job)
+
+	for _, labels := range []map[string]string{
+		{JobLabel: "yes please"},
+		{JobCompletionsLabel: "2"},
+		{JobLabel: "true", JobCompletionsLabel: "0"},
+		{JobLabel: "true", JobBackoffLimitLabel: "-1"},
+		{JobLabel: "true", AutoscaleMaxLabel: "3"},
+		{ScheduleLabel: "0 3 * *"},
+		{ScheduleLabel: "@sometimes"},
+		{ScheduleLabel: "0 3 * * %"},
+	} {
+		_, err := ParseJob("web", &project.ServiceConfig{Labels: project.NewSliceorMap(labels)})
+		assert.NotNil(t, err, "%v", labels)
+	}
+
+	_, err = ConvertToJob("demo", "web", &project.ServiceConfig{Image: "web", Restart: "always"}, &JobConfig{})
+	assert.NotNil(t, err)
+}
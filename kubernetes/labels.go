@@ -0,0 +1,164 @@
+package kubernetes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/util/validation"
+)
+
+// Label represents a kubernetes label set by kompose on generated objects.
+type Label string
+
+// Kompose default labels.
+const (
+	PROJECT = Label("kompose.project")
+	SERVICE = Label("kompose.service")
+	HASH    = Label("kompose.config-hash")
+)
+
+// Str returns the label name.
+func (f Label) Str() string {
+	return string(f)
+}
+
+// Eq returns a label selector matching the specified value.
+func (f Label) Eq(value string) labels.Selector {
+	return labels.Set{string(f): value}.AsSelector()
+}
+
+// DirectivePrefix is the prefix of the labels reserved for kompose: they are
+// directives to the converters and are not copied to the generated objects.
+const DirectivePrefix = "kompose."
+
+var directives = map[string]bool{}
+
+// RegisterDirective declares labels as kompose directives, so that services
+// may set them. Converters declare the directives they read in their init.
+func RegisterDirective(labels ...string) {
+	for _, label := range labels {
+		directives[label] = true
+	}
+}
+
+// ValidateDirectives checks the kompose labels of a service are known
+// directives, so that a typo is not silently ignored.
+func ValidateDirectives(name string, c *project.ServiceConfig) error {
+	keys := []string{}
+	for key := range c.Labels.MapParts() {
+		if strings.HasPrefix(key, DirectivePrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch {
+		case key == PROJECT.Str() || key == SERVICE.Str() || key == HASH.Str():
+			return project.NewValidationError(name, project.LabelField(key), "the label is set by kompose and cannot be overridden")
+		case !directives[key]:
+			return project.NewValidationError(name, project.LabelField(key), "unknown label, labels starting with %s are reserved for kompose directives", DirectivePrefix)
+		}
+	}
+	return nil
+}
+
+// ValidateLabels checks the labels of a service can be copied to its objects:
+// its kompose labels must be known directives and its other labels must not
+// override the ones kompose selects the pods with.
+func ValidateLabels(name string, c *project.ServiceConfig) error {
+	if err := ValidateDirectives(name, c); err != nil {
+		return err
+	}
+	if _, ok := c.Labels.MapParts()["service"]; ok {
+		return project.NewValidationError(name, project.LabelField("service"), "the label is set by kompose and cannot be overridden")
+	}
+	return nil
+}
+
+// UserLabels returns the labels of a service to copy to its objects, besides
+// the kompose directives. The labels that are not valid kubernetes labels are
+// returned as annotations instead. Either map is nil when empty.
+func UserLabels(c *project.ServiceConfig) (labels, annotations map[string]string) {
+	for key, value := range c.Labels.MapParts() {
+		if strings.HasPrefix(key, DirectivePrefix) {
+			continue
+		}
+		if validation.IsQualifiedName(key) && validation.IsValidLabelValue(value) {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[key] = value
+		} else {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+		}
+	}
+	return labels, annotations
+}
+
+// ServiceAnnotations returns the annotations set on the objects generated for
+// a service, its labels that are not valid kubernetes labels.
+func ServiceAnnotations(c *project.ServiceConfig) map[string]string {
+	_, annotations := UserLabels(c)
+	return annotations
+}
+
+// ServiceLabels returns the labels stamped on every object generated for the
+// specified service of the project, its own labels along with the kompose ones.
+func ServiceLabels(projectName, name string, config *project.ServiceConfig) map[string]string {
+	result, _ := UserLabels(config)
+	if result == nil {
+		result = map[string]string{}
+	}
+	result["service"] = name
+	result[PROJECT.Str()] = projectName
+	result[SERVICE.Str()] = name
+	result[HASH.Str()] = project.GetServiceHash(name, config)
+	return result
+}
+
+// SharedLabels returns the labels of an object shared by the services of the
+// project, like the image pull secret. It is labelled as a service named after
+// the object, so that it is selected with the other objects of the project.
+func SharedLabels(projectName, name string) map[string]string {
+	return map[string]string{
+		"service":     name,
+		PROJECT.Str(): projectName,
+		SERVICE.Str(): name,
+	}
+}
+
+// ProjectSelector returns a label selector matching every object of the project.
+func ProjectSelector(p *project.Project) labels.Selector {
+	return PROJECT.Eq(p.Name)
+}
+
+// ServiceSelector returns a label selector matching the objects generated for
+// the specified service of the project.
+func ServiceSelector(p *project.Project, name string) labels.Selector {
+	return labels.Set{
+		PROJECT.Str(): p.Name,
+		SERVICE.Str(): name,
+	}.AsSelector()
+}
+
+// OrphanSelector returns a label selector matching the objects of the project
+// whose service is no longer defined in the compose file.
+func OrphanSelector(p *project.Project) labels.Selector {
+	names := []string{}
+	for name := range p.Configs {
+		names = append(names, name)
+	}
+
+	selector := ProjectSelector(p)
+	if len(names) > 0 {
+		selector = selector.Add(SERVICE.Str(), labels.NotInOperator, names)
+	}
+	return selector
+}
@@ -0,0 +1,109 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+func newTestProject() *project.Project {
+	p := project.NewProject(&project.Context{})
+	p.Name = "demo"
+	p.Configs["web"] = &project.ServiceConfig{Image: "nginx"}
+	p.Configs["redis"] = &project.ServiceConfig{Image: "redis"}
+	return p
+}
+
+func TestServiceLabels(t *testing.T) {
+	p := newTestProject()
+	l := ServiceLabels(p.Name, "web", p.Configs["web"])
+
+	assert.Equal(t, "web", l["service"])
+	assert.Equal(t, "demo", l[PROJECT.Str()])
+	assert.Equal(t, "web", l[SERVICE.Str()])
+	assert.Equal(t, project.GetServiceHash("web", p.Configs["web"]), l[HASH.Str()])
+}
+
+func TestServiceSelector(t *testing.T) {
+	p := newTestProject()
+	selector := ServiceSelector(p, "web")
+
+	assert.True(t, selector.Matches(labels.Set(ServiceLabels(p.Name, "web", p.Configs["web"]))))
+	assert.False(t, selector.Matches(labels.Set(ServiceLabels(p.Name, "redis", p.Configs["redis"]))))
+	assert.False(t, selector.Matches(labels.Set{PROJECT.Str(): "other", SERVICE.Str(): "web"}))
+}
+
+func TestOrphanSelector(t *testing.T) {
+	p := newTestProject()
+	selector := OrphanSelector(p)
+
+	assert.False(t, selector.Matches(labels.Set(ServiceLabels(p.Name, "web", p.Configs["web"]))))
+	assert.False(t, selector.Matches(labels.Set(ServiceLabels(p.Name, "redis", p.Configs["redis"]))))
+	assert.True(t, selector.Matches(labels.Set{PROJECT.Str(): "demo", SERVICE.Str(): "db"}))
+	assert.False(t, selector.Matches(labels.Set{PROJECT.Str(): "other", SERVICE.Str(): "db"}))
+}
+
+func TestUserLabels(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image: "nginx",
+		Labels: project.NewSliceorMap(map[string]string{
+			"tier":                   "frontend",
+			"example.com/team":       "web",
+			"description":            "The public web site",
+			"com.example/bad key":    "x",
+			AutoscaleMaxLabel:        "3",
+			"kompose.probe.liveness": "tcp:80",
+		}),
+	}
+
+	l, annotations := UserLabels(sc)
+	assert.Equal(t, map[string]string{"tier": "frontend", "example.com/team": "web"}, l)
+	assert.Equal(t, map[string]string{"description": "The public web site", "com.example/bad key": "x"}, annotations)
+
+	objects, err := ConvertToAPI("demo", "web", sc)
+	assert.Nil(t, err)
+	rc := objects.ReplicationController
+	assert.Equal(t, "frontend", rc.Labels["tier"])
+	assert.Equal(t, "web", rc.Labels[SERVICE.Str()])
+	assert.Equal(t, "frontend", rc.Spec.Template.Labels["tier"])
+	assert.Equal(t, annotations, rc.Annotations)
+	assert.Equal(t, annotations, rc.Spec.Template.Annotations)
+	assert.Equal(t, "frontend", objects.Deployment.Labels["tier"])
+
+	l, annotations = UserLabels(&project.ServiceConfig{})
+	assert.Nil(t, l)
+	assert.Nil(t, annotations)
+}
+
+func TestValidateLabels(t *testing.T) {
+	RegisterDirective("kompose.test")
+
+	for _, valid := range []map[string]string{
+		{},
+		{"tier": "frontend"},
+		{AutoscaleMaxLabel: "3", "kompose.probe.readiness.delay": "5", JobLabel: "false"},
+		{"kompose.test": "x"},
+	} {
+		assert.Nil(t, ValidateLabels("web", &project.ServiceConfig{Labels: project.NewSliceorMap(valid)}), "%v", valid)
+	}
+
+	for _, invalid := range []map[string]string{
+		{"kompose.autoscale.maxi": "3"},
+		{"kompose.probe.readiness.dealy": "5"},
+		{PROJECT.Str(): "other"},
+		{"service": "other"},
+	} {
+		assert.NotNil(t, ValidateLabels("web", &project.ServiceConfig{Labels: project.NewSliceorMap(invalid)}), "%v", invalid)
+	}
+
+	assert.Nil(t, ValidateDirectives("web", &project.ServiceConfig{Labels: project.NewSliceorMap(map[string]string{"service": "other"})}))
+
+	_, err := ConvertToAPI("demo", "web", &project.ServiceConfig{
+		Image:  "nginx",
+		Labels: project.NewSliceorMap(map[string]string{"kompose.autoscale.mx": "3"}),
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,165 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/util"
+)
+
+// Network policies are not part of the API version kompose is built against,
+// the following types mirror the extensions/v1beta1 ones.
+
+// NetworkPolicy describes which pods may connect to the pods it selects.
+type NetworkPolicy struct {
+	unversioned.TypeMeta `json:",inline"`
+	api.ObjectMeta       `json:"metadata,omitempty"`
+	Spec                 NetworkPolicySpec `json:"spec"`
+}
+
+// NetworkPolicySpec selects the pods the policy applies to and the allowed
+// ingress traffic. An empty ingress denies all traffic.
+type NetworkPolicySpec struct {
+	PodSelector LabelSelector              `json:"podSelector"`
+	Ingress     []NetworkPolicyIngressRule `json:"ingress"`
+}
+
+// NetworkPolicyIngressRule allows traffic matching both its ports and sources.
+// An empty list matches all ports or sources.
+type NetworkPolicyIngressRule struct {
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
+	From  []NetworkPolicyPeer `json:"from,omitempty"`
+}
+
+// NetworkPolicyPort is a port on which traffic is allowed.
+type NetworkPolicyPort struct {
+	Protocol api.Protocol     `json:"protocol,omitempty"`
+	Port     util.IntOrString `json:"port"`
+}
+
+// NetworkPolicyPeer selects the pods traffic is allowed from.
+type NetworkPolicyPeer struct {
+	PodSelector *LabelSelector `json:"podSelector,omitempty"`
+}
+
+// LabelSelector selects the objects having all the labels.
+type LabelSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ConvertToNetworkPolicy generates the network policy of a service, mirroring
+// the isolation of compose: its pods only accept connections from the services
+// depending on it, on the ports it publishes or exposes, and from anywhere on
+// the ports it publishes.
+func ConvertToNetworkPolicy(p *project.Project, name string) (*NetworkPolicy, error) {
+	c := p.Configs[name]
+
+	ports, err := containerPorts(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	published := []NetworkPolicyPort{}
+	for _, port := range ports {
+		published = append(published, NetworkPolicyPort{
+			Protocol: api.ProtocolTCP,
+			Port:     util.NewIntOrStringFromInt(port.ContainerPort),
+		})
+	}
+
+	exposed, err := exposedPorts(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	ingress := []NetworkPolicyIngressRule{}
+
+	dependents := []string{}
+	for other := range p.Configs {
+		for _, dependency := range DependentServices(p, other) {
+			if dependency == name {
+				dependents = append(dependents, other)
+			}
+		}
+	}
+	sort.Strings(dependents)
+
+	if len(dependents) > 0 {
+		// Without any port declared, the dependents may connect on all of them.
+		rule := NetworkPolicyIngressRule{}
+		for _, port := range append(append([]NetworkPolicyPort{}, published...), exposed...) {
+			if !hasPolicyPort(rule.Ports, port) {
+				rule.Ports = append(rule.Ports, port)
+			}
+		}
+		for _, dependent := range dependents {
+			rule.From = append(rule.From, NetworkPolicyPeer{
+				PodSelector: &LabelSelector{
+					MatchLabels: map[string]string{
+						PROJECT.Str(): p.Name,
+						SERVICE.Str(): dependent,
+					},
+				},
+			})
+		}
+		ingress = append(ingress, rule)
+	}
+
+	if len(published) > 0 {
+		ingress = append(ingress, NetworkPolicyIngressRule{Ports: published})
+	}
+
+	return &NetworkPolicy{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:   name,
+			Labels: ServiceLabels(p.Name, name, c),
+		},
+		Spec: NetworkPolicySpec{
+			PodSelector: LabelSelector{
+				MatchLabels: map[string]string{
+					PROJECT.Str(): p.Name,
+					SERVICE.Str(): name,
+				},
+			},
+			Ingress: ingress,
+		},
+	}, nil
+}
+
+// exposedPorts returns the ports of the expose key of a service, given as
+// port or port/protocol.
+func exposedPorts(name string, c *project.ServiceConfig) ([]NetworkPolicyPort, error) {
+	ports := []NetworkPolicyPort{}
+	for i, expose := range c.Expose {
+		port, protocol := strings.TrimSpace(expose), api.ProtocolTCP
+		if i := strings.Index(port, "/"); i >= 0 {
+			port, protocol = port[:i], api.Protocol(strings.ToUpper(port[i+1:]))
+		}
+
+		number, err := strconv.Atoi(port)
+		if err != nil || (protocol != api.ProtocolTCP && protocol != api.ProtocolUDP) {
+			return nil, project.NewValidationError(name, fmt.Sprintf("expose[%d]", i), "invalid exposed port %s", expose)
+		}
+		ports = append(ports, NetworkPolicyPort{Protocol: protocol, Port: util.NewIntOrStringFromInt(number)})
+	}
+	return ports, nil
+}
+
+func hasPolicyPort(ports []NetworkPolicyPort, port NetworkPolicyPort) bool {
+	for _, other := range ports {
+		if other.Protocol == port.Protocol && other.Port.IntVal == port.Port.IntVal {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/util"
+)
+
+func TestConvertToNetworkPolicy(t *testing.T) {
+	p := newLinkedProject()
+
+	policy, err := ConvertToNetworkPolicy(p, "redis")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{PROJECT.Str(): "demo", SERVICE.Str(): "redis"}, policy.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []NetworkPolicyIngressRule{
+		{
+			Ports: []NetworkPolicyPort{{Protocol: api.ProtocolTCP, Port: util.NewIntOrStringFromInt(6379)}},
+			From: []NetworkPolicyPeer{
+				{PodSelector: &LabelSelector{MatchLabels: map[string]string{PROJECT.Str(): "demo", SERVICE.Str(): "web"}}},
+			},
+		},
+		{
+			Ports: []NetworkPolicyPort{{Protocol: api.ProtocolTCP, Port: util.NewIntOrStringFromInt(6379)}},
+		},
+	}, policy.Spec.Ingress)
+}
+
+func TestConvertToNetworkPolicyExpose(t *testing.T) {
+	p := newLinkedProject()
+	p.Configs["redis"].Expose = []string{"6379", "26379", "5353/udp"}
+
+	policy, err := ConvertToNetworkPolicy(p, "redis")
+	assert.Nil(t, err)
+	// The dependents may connect on the exposed ports, which are not published.
+	assert.Equal(t, []NetworkPolicyPort{
+		{Protocol: api.ProtocolTCP, Port: util.NewIntOrStringFromInt(6379)},
+		{Protocol: api.ProtocolTCP, Port: util.NewIntOrStringFromInt(26379)},
+		{Protocol: api.ProtocolUDP, Port: util.NewIntOrStringFromInt(5353)},
+	}, policy.Spec.Ingress[0].Ports)
+	assert.Equal(t, []NetworkPolicyPort{{Protocol: api.ProtocolTCP, Port: util.NewIntOrStringFromInt(6379)}}, policy.Spec.Ingress[1].Ports)
+
+	p.Configs["redis"].Expose = []string{"redis"}
+	_, err = ConvertToNetworkPolicy(p, "redis")
+	assert.NotNil(t, err)
+}
+
+func TestConvertToNetworkPolicyIsolated(t *testing.T) {
+	p := newLinkedProject()
+
+	policy, err := ConvertToNetworkPolicy(p, "web")
+	assert.Nil(t, err)
+
+	// Nothing links to web and it publishes no port, so it denies all ingress.
+	data, err := json.Marshal(policy.Spec)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"ingress":[]`)
+}
+
+func TestConvertToNetworkPolicyDependencies(t *testing.T) {
+	p := newLinkedProject()
+	p.Configs["sidecar"] = &project.ServiceConfig{Image: "envoy", Net: "container:redis"}
+	p.Configs["backup"] = &project.ServiceConfig{Image: "busybox", VolumesFrom: []string{"redis:ro"}}
+
+	policy, err := ConvertToNetworkPolicy(p, "redis")
+	assert.Nil(t, err)
+	// Services sharing its network namespace or volumes may connect to it, like the ones linking to it.
+	assert.Equal(t, []NetworkPolicyPeer{
+		{PodSelector: &LabelSelector{MatchLabels: map[string]string{PROJECT.Str(): "demo", SERVICE.Str(): "backup"}}},
+		{PodSelector: &LabelSelector{MatchLabels: map[string]string{PROJECT.Str(): "demo", SERVICE.Str(): "sidecar"}}},
+		{PodSelector: &LabelSelector{MatchLabels: map[string]string{PROJECT.Str(): "demo", SERVICE.Str(): "web"}}},
+	}, policy.Spec.Ingress[0].From)
+}
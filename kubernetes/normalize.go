@@ -0,0 +1,155 @@
+package kubernetes
+
+// Objects are compared on the fields the converter sets, decoded from JSON:
+// the fields populated by the API server (status, resource version, cluster
+// IP, defaults, …) and the ones added by other controllers, like the pod
+// template hash of deployments, are left out of the live objects.
+
+// convertedFields holds the fields the converter only sets on some objects, or
+// with some options: they are compared even when the generated object lacks
+// them, so that their removal shows.
+var convertedFields = map[string]bool{
+	"args":                        true,
+	"command":                     true,
+	"env":                         true,
+	"imagePullSecrets":            true,
+	"livenessProbe":               true,
+	"ports":                       true,
+	"readinessProbe":              true,
+	"securityContext":             true,
+	InitContainersAlphaAnnotation: true,
+	InitContainersBetaAnnotation:  true,
+}
+
+// scaledKinds holds the kinds of objects whose scale is not part of the
+// compose file, it is set by scale or by the autoscalers.
+var scaledKinds = map[string]bool{
+	"Deployment":            true,
+	"ReplicationController": true,
+}
+
+// NormalizeGenerated returns a copy of an object generated by the converter
+// without its status, its empty fields, which the API server defaults, nor its
+// scale.
+func NormalizeGenerated(object map[string]interface{}) map[string]interface{} {
+	result, _ := withoutEmpty(object).(map[string]interface{})
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	delete(result, "status")
+
+	if kind, _ := object["kind"].(string); scaledKinds[kind] {
+		if spec, ok := result["spec"].(map[string]interface{}); ok {
+			delete(spec, "replicas")
+		}
+	}
+	return result
+}
+
+// NormalizeLive returns the fields of a live object set by the normalized
+// generated one, or that the converter sets on other objects. The items of a
+// list missing from the generated one are kept whole. Without a generated
+// object, the status and the metadata populated by the API server are left out.
+func NormalizeLive(live, generated map[string]interface{}) map[string]interface{} {
+	if generated != nil {
+		result, _ := managedFields(live, generated).(map[string]interface{})
+		return result
+	}
+
+	result := map[string]interface{}{}
+	for key, value := range live {
+		if key != "status" && key != "metadata" {
+			result[key] = value
+		}
+	}
+	if metadata, ok := live["metadata"].(map[string]interface{}); ok {
+		kept := map[string]interface{}{}
+		for _, key := range []string{"name", "labels", "annotations"} {
+			if value, ok := metadata[key]; ok {
+				kept[key] = value
+			}
+		}
+		result["metadata"] = kept
+	}
+	return result
+}
+
+// managedFields returns the parts of a live value set by the generated one.
+func managedFields(live, generated interface{}) interface{} {
+	switch generated := generated.(type) {
+	case map[string]interface{}:
+		liveMap, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		result := map[string]interface{}{}
+		for key, liveValue := range liveMap {
+			value, ok := generated[key]
+			switch {
+			case ok:
+				result[key] = managedFields(liveValue, value)
+			case convertedFields[key]:
+				if liveValue = withoutEmpty(liveValue); liveValue != nil {
+					result[key] = liveValue
+				}
+			default:
+				// The converted fields may be nested in the missing field.
+				if _, ok := liveValue.(map[string]interface{}); ok {
+					if nested := managedFields(liveValue, map[string]interface{}{}).(map[string]interface{}); len(nested) > 0 {
+						result[key] = nested
+					}
+				}
+			}
+		}
+		return result
+	case []interface{}:
+		liveList, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		result := []interface{}{}
+		for i, liveValue := range liveList {
+			if i < len(generated) {
+				liveValue = managedFields(liveValue, generated[i])
+			}
+			result = append(result, liveValue)
+		}
+		return result
+	}
+	return live
+}
+
+// withoutEmpty returns a copy of a value without its null, empty string, empty
+// map and empty list fields.
+func withoutEmpty(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, field := range value {
+			if field = withoutEmpty(field); field != nil {
+				result[key] = field
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case []interface{}:
+		result := []interface{}{}
+		for _, item := range value {
+			if item = withoutEmpty(item); item == nil {
+				item = map[string]interface{}{}
+			}
+			result = append(result, item)
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case string:
+		if value == "" {
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeGenerated(t *testing.T) {
+	generated := map[string]interface{}{
+		"kind":     "ReplicationController",
+		"metadata": map[string]interface{}{"name": "web", "creationTimestamp": nil},
+		"spec": map[string]interface{}{
+			"replicas": 1.0,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "imagePullPolicy": "", "resources": map[string]interface{}{}},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"replicas": 0.0},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"kind":     "ReplicationController",
+		"metadata": map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "web"}},
+				},
+			},
+		},
+	}, NormalizeGenerated(generated))
+}
+
+func TestNormalizeLive(t *testing.T) {
+	generated := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "web", "image": "nginx"}},
+				},
+			},
+		},
+	}
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "web",
+			"resourceVersion": "42",
+			"annotations":     map[string]interface{}{InitContainersBetaAnnotation: "[]", "deployment.kubernetes.io/revision": "2"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": 3.0,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"dnsPolicy":       "ClusterFirst",
+					"securityContext": map[string]interface{}{},
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.9", "terminationMessagePath": "/dev/termination-log"},
+						map[string]interface{}{"name": "sidecar"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"replicas": 3.0},
+	}
+
+	// The server defaults are left out, the converted fields and items missing
+	// from the generated object are kept.
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "web",
+			"annotations": map[string]interface{}{InitContainersBetaAnnotation: "[]"},
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "nginx:1.9"},
+						map[string]interface{}{"name": "sidecar"},
+					},
+				},
+			},
+		},
+	}, NormalizeLive(live, generated))
+
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "web",
+			"annotations": map[string]interface{}{InitContainersBetaAnnotation: "[]", "deployment.kubernetes.io/revision": "2"},
+		},
+		"spec": live["spec"],
+	}, NormalizeLive(live, nil))
+}
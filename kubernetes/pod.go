@@ -0,0 +1,106 @@
+package kubernetes
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/logger"
+
+	"k8s.io/kubernetes/pkg/api"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+// PollInterval is the interval at which the cluster state is polled while waiting.
+const PollInterval = time.Second
+
+// PodStatus holds the state of a pod backing a compose service.
+type PodStatus struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Ready    bool   `json:"ready"`
+	Restarts int    `json:"restarts"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// NewPodStatus summarizes the phase, readiness and restarts of a pod. The reason
+// holds the first waiting or termination reason reported by its containers.
+func NewPodStatus(pod *api.Pod) PodStatus {
+	status := PodStatus{
+		Name:  pod.Name,
+		Phase: string(pod.Status.Phase),
+		Ready: api.IsPodReady(pod),
+	}
+
+	for _, container := range pod.Status.ContainerStatuses {
+		status.Restarts += container.RestartCount
+		if status.Reason != "" {
+			continue
+		}
+		if container.State.Waiting != nil {
+			status.Reason = container.State.Waiting.Reason
+		} else if container.State.Terminated != nil {
+			status.Reason = container.State.Terminated.Reason
+		}
+	}
+
+	return status
+}
+
+// WaitForPodsGone polls until no pod matches the selector or the timeout expires.
+func WaitForPodsGone(client *client.Client, selector labels.Selector, timeout time.Duration) error {
+	return wait.PollImmediate(PollInterval, timeout, func() (bool, error) {
+		pods, err := client.Pods(api.NamespaceDefault).List(selector, fields.Everything())
+		if err != nil {
+			return false, err
+		}
+		logrus.Debugf("Waiting for %d pods to terminate", len(pods.Items))
+		return len(pods.Items) == 0, nil
+	})
+}
+
+// LogOptions holds the options used to retrieve pod logs. A negative TailLines
+// retrieves all the lines.
+type LogOptions struct {
+	Follow       bool
+	TailLines    int64
+	SinceSeconds int64
+}
+
+// StreamPodLogs copies the logs of a pod container to the logger, line by line.
+func StreamPodLogs(client *client.Client, pod, container string, options LogOptions, l logger.Logger) error {
+	request := client.Get().
+		Namespace(api.NamespaceDefault).
+		Resource("pods").
+		Name(pod).
+		SubResource("log").
+		Param("container", container)
+
+	if options.Follow {
+		request = request.Param("follow", "true")
+	}
+	if options.TailLines >= 0 {
+		request = request.Param("tailLines", strconv.FormatInt(options.TailLines, 10))
+	}
+	if options.SinceSeconds > 0 {
+		request = request.Param("sinceSeconds", strconv.FormatInt(options.SinceSeconds, 10))
+	}
+
+	stream, err := request.Stream()
+	if err != nil {
+		return fmt.Errorf("Failed to get logs of %s: %v", pod, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		l.Out(append(scanner.Bytes(), '\n'))
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,297 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+	shlex "github.com/flynn/go-shlex"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/util"
+)
+
+// Probe labels hold the probe specification, like http:8080/healthz,
+// tcp:6379 or exec:pg_isready, and are refined by options such as
+// kompose.probe.readiness.delay=10.
+const (
+	ReadinessProbeLabel = "kompose.probe.readiness"
+	LivenessProbeLabel  = "kompose.probe.liveness"
+)
+
+// Probe options, in seconds except for the thresholds. The threshold is the
+// number of consecutive failures failing the probe, and the success threshold
+// the number of consecutive successes passing it again.
+var probeOptions = []string{"delay", "timeout", "period", "threshold", "success-threshold"}
+
+// ProbeTuning holds the probe fields added after the API version kompose is
+// built against, which api.Probe lacks. EncodeObject sets them on the probes
+// of the encoded objects.
+type ProbeTuning struct {
+	PeriodSeconds    int64 `json:"periodSeconds,omitempty"`
+	SuccessThreshold int64 `json:"successThreshold,omitempty"`
+	FailureThreshold int64 `json:"failureThreshold,omitempty"`
+}
+
+func init() {
+	for _, label := range []string{ReadinessProbeLabel, LivenessProbeLabel} {
+		RegisterDirective(label)
+		for _, option := range probeOptions {
+			RegisterDirective(label + "." + option)
+		}
+	}
+}
+
+// probes returns the readiness and liveness probes of a service, as set by its labels.
+func probes(name string, c *project.ServiceConfig) (readiness, liveness *api.Probe, err error) {
+	labels := c.Labels.MapParts()
+
+	if readiness, err = labelProbe(name, labels, ReadinessProbeLabel); err != nil {
+		return nil, nil, err
+	}
+
+	if liveness, err = labelProbe(name, labels, LivenessProbeLabel); err != nil {
+		return nil, nil, err
+	}
+
+	// The tunings are only encoded by EncodeObject, but checked with the probes.
+	if _, _, err := ProbeTunings(name, c); err != nil {
+		return nil, nil, err
+	}
+
+	return readiness, liveness, nil
+}
+
+func labelProbe(name string, labels map[string]string, label string) (*api.Probe, error) {
+	options, err := ProbeOptions(name, labels, label)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, ok := labels[label]
+	if !ok {
+		return nil, nil
+	}
+
+	probe, err := ParseProbe(spec)
+	if err != nil {
+		return nil, project.NewValidationError(name, project.LabelField(label), "%v", err)
+	}
+
+	probe.InitialDelaySeconds = options["delay"]
+	probe.TimeoutSeconds = options["timeout"]
+
+	return probe, nil
+}
+
+// ProbeTunings returns the fields of the readiness and liveness probes of a
+// service which api.Probe lacks, nil for the probes which set none of them.
+func ProbeTunings(name string, c *project.ServiceConfig) (readiness, liveness *ProbeTuning, err error) {
+	labels := c.Labels.MapParts()
+
+	if readiness, err = labelProbeTuning(name, labels, ReadinessProbeLabel); err != nil {
+		return nil, nil, err
+	}
+
+	if liveness, err = labelProbeTuning(name, labels, LivenessProbeLabel); err != nil {
+		return nil, nil, err
+	}
+
+	return readiness, liveness, nil
+}
+
+func labelProbeTuning(name string, labels map[string]string, label string) (*ProbeTuning, error) {
+	options, err := ProbeOptions(name, labels, label)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API rejects zero periods and thresholds.
+	for _, option := range []string{"period", "threshold", "success-threshold"} {
+		if number, ok := options[option]; ok && number == 0 {
+			return nil, project.NewValidationError(name, project.LabelField(label+"."+option), "must be at least 1")
+		}
+	}
+	if label == LivenessProbeLabel && options["success-threshold"] > 1 {
+		return nil, project.NewValidationError(name, project.LabelField(label+".success-threshold"), "must be 1 for liveness probes")
+	}
+
+	tuning := &ProbeTuning{
+		PeriodSeconds:    options["period"],
+		SuccessThreshold: options["success-threshold"],
+		FailureThreshold: options["threshold"],
+	}
+	if *tuning == (ProbeTuning{}) {
+		return nil, nil
+	}
+	return tuning, nil
+}
+
+// EncodeObject encodes an object generated for a service in JSON, with the
+// fields of its probes which api.Probe lacks set on the container of the
+// service. The keys of the objects whose probes are tuned are sorted.
+func EncodeObject(object interface{}, name string, c *project.ServiceConfig) ([]byte, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	readiness, liveness, err := ProbeTunings(name, c)
+	if err != nil || (readiness == nil && liveness == nil) {
+		return data, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	tunings := map[string]*ProbeTuning{"readinessProbe": readiness, "livenessProbe": liveness}
+	if err := tuneProbes(decoded, name, tunings); err != nil {
+		return nil, err
+	}
+	return json.Marshal(decoded)
+}
+
+// tuneProbes sets the tunings on the probes of the containers named after
+// the service found in the decoded object, at any depth.
+func tuneProbes(value interface{}, name string, tunings map[string]*ProbeTuning) error {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := tuneProbes(item, name, tunings); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if containers, ok := item.([]interface{}); ok && key == "containers" {
+				for _, container := range containers {
+					if err := tuneContainerProbes(container, name, tunings); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := tuneProbes(item, name, tunings); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func tuneContainerProbes(value interface{}, name string, tunings map[string]*ProbeTuning) error {
+	container, ok := value.(map[string]interface{})
+	if !ok || container["name"] != name {
+		return nil
+	}
+
+	for key, tuning := range tunings {
+		probe, ok := container[key].(map[string]interface{})
+		if !ok || tuning == nil {
+			continue
+		}
+		data, err := json.Marshal(tuning)
+		if err != nil {
+			return err
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		for field, number := range fields {
+			probe[field] = number
+		}
+	}
+	return nil
+}
+
+// ProbeOptions returns the options of the probe set by a label, like 10 for
+// delay with kompose.probe.readiness.delay=10, by option name. Options are
+// rejected if the probe itself is not set.
+func ProbeOptions(name string, labels map[string]string, label string) (map[string]int64, error) {
+	_, hasProbe := labels[label]
+
+	options := map[string]int64{}
+	for _, option := range probeOptions {
+		key := label + "." + option
+		value, ok := labels[key]
+		if !ok {
+			continue
+		}
+		if !hasProbe {
+			return nil, project.NewValidationError(name, project.LabelField(key), "%s is not set", label)
+		}
+
+		number, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || number < 0 {
+			return nil, project.NewValidationError(name, project.LabelField(key), "must be a positive number, got %s", value)
+		}
+		options[option] = number
+	}
+
+	return options, nil
+}
+
+// ParseProbe parses a probe specification: http:PORT[/PATH], tcp:PORT or
+// exec:COMMAND.
+func ParseProbe(spec string) (*api.Probe, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("%s, expected http:PORT/PATH, tcp:PORT or exec:COMMAND", spec)
+	}
+
+	kind, value := parts[0], parts[1]
+	probe := &api.Probe{}
+
+	switch kind {
+	case "http":
+		port, path := value, "/"
+		if i := strings.Index(value, "/"); i >= 0 {
+			port, path = value[:i], value[i:]
+		}
+		portNumber, err := parseProbePort(port)
+		if err != nil {
+			return nil, err
+		}
+		probe.HTTPGet = &api.HTTPGetAction{
+			Path:   path,
+			Port:   util.NewIntOrStringFromInt(portNumber),
+			Scheme: api.URISchemeHTTP,
+		}
+	case "tcp":
+		portNumber, err := parseProbePort(value)
+		if err != nil {
+			return nil, err
+		}
+		probe.TCPSocket = &api.TCPSocketAction{
+			Port: util.NewIntOrStringFromInt(portNumber),
+		}
+	case "exec":
+		command, err := shlex.Split(value)
+		if err != nil || len(command) == 0 {
+			return nil, fmt.Errorf("Invalid command %s", value)
+		}
+		probe.Exec = &api.ExecAction{
+			Command: command,
+		}
+	default:
+		return nil, fmt.Errorf("Unknown probe type %s in %s, expected http, tcp or exec", kind, spec)
+	}
+
+	return probe, nil
+}
+
+func parseProbePort(port string) (int, error) {
+	number, err := strconv.Atoi(port)
+	if err != nil || number < 1 || number > 65535 {
+		return 0, fmt.Errorf("Invalid port %s", port)
+	}
+	return number, nil
+}
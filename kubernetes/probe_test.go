@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/util"
+)
+
+func TestParseProbe(t *testing.T) {
+	probe, err := ParseProbe("http:8080/healthz")
+	assert.Nil(t, err)
+	assert.Equal(t, "/healthz", probe.HTTPGet.Path)
+	assert.Equal(t, util.NewIntOrStringFromInt(8080), probe.HTTPGet.Port)
+
+	probe, err = ParseProbe("http:80")
+	assert.Nil(t, err)
+	assert.Equal(t, "/", probe.HTTPGet.Path)
+
+	probe, err = ParseProbe("tcp:6379")
+	assert.Nil(t, err)
+	assert.Equal(t, util.NewIntOrStringFromInt(6379), probe.TCPSocket.Port)
+
+	probe, err = ParseProbe("exec:pg_isready -U 'postgres user'")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pg_isready", "-U", "postgres user"}, probe.Exec.Command)
+
+	for _, spec := range []string{"", "http", "http:", "http:web/healthz", "tcp:70000", "udp:53", "exec:"} {
+		_, err := ParseProbe(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestProbes(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image: "redis",
+		Labels: project.NewSliceorMap(map[string]string{
+			"kompose.probe.readiness":         "tcp:6379",
+			"kompose.probe.readiness.delay":   "5",
+			"kompose.probe.readiness.timeout": "2",
+			"kompose.probe.liveness":          "exec:redis-cli ping",
+		}),
+	}
+
+	template, err := ConvertToPodTemplate("demo", "redis", sc)
+	assert.Nil(t, err)
+
+	container := template.Spec.Containers[0]
+	assert.Equal(t, int64(5), container.ReadinessProbe.InitialDelaySeconds)
+	assert.Equal(t, int64(2), container.ReadinessProbe.TimeoutSeconds)
+	assert.Equal(t, &api.ExecAction{Command: []string{"redis-cli", "ping"}}, container.LivenessProbe.Exec)
+}
+
+func TestProbeOptions(t *testing.T) {
+	options, err := ProbeOptions("api", map[string]string{
+		"kompose.probe.liveness":           "tcp:8080",
+		"kompose.probe.liveness.period":    "20",
+		"kompose.probe.liveness.threshold": " 3",
+		"kompose.probe.readiness.delay":    "5",
+	}, LivenessProbeLabel)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"period": 20, "threshold": 3}, options)
+}
+
+func TestProbesInvalid(t *testing.T) {
+	for _, invalid := range []struct {
+		field  string
+		labels map[string]string
+	}{
+		{"labels[kompose.probe.readiness]", map[string]string{"kompose.probe.readiness": "http:port"}},
+		{"labels[kompose.probe.liveness.delay]", map[string]string{"kompose.probe.liveness": "tcp:6379", "kompose.probe.liveness.delay": "soon"}},
+		{"labels[kompose.probe.liveness.delay]", map[string]string{"kompose.probe.liveness.delay": "5"}},
+		{"labels[kompose.probe.liveness.period]", map[string]string{"kompose.probe.liveness": "tcp:6379", "kompose.probe.liveness.period": "0"}},
+		{"labels[kompose.probe.liveness.success-threshold]", map[string]string{"kompose.probe.liveness": "tcp:6379", "kompose.probe.liveness.success-threshold": "2"}},
+	} {
+		sc := &project.ServiceConfig{Image: "redis", Labels: project.NewSliceorMap(invalid.labels)}
+		_, _, err := probes("redis", sc)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "service redis")
+		if assert.IsType(t, &project.ValidationError{}, err) {
+			assert.Equal(t, invalid.field, err.(*project.ValidationError).Field)
+		}
+	}
+}
+
+func TestEncodeObject(t *testing.T) {
+	sc := &project.ServiceConfig{
+		Image: "redis",
+		Labels: project.NewSliceorMap(map[string]string{
+			"kompose.probe.readiness":                   "tcp:6379",
+			"kompose.probe.readiness.period":            "5",
+			"kompose.probe.readiness.success-threshold": "2",
+			"kompose.probe.liveness":                    "exec:redis-cli ping",
+			"kompose.probe.liveness.threshold":          "3",
+		}),
+	}
+
+	objects, err := ConvertToAPI("demo", "redis", sc)
+	assert.Nil(t, err)
+
+	for _, object := range []interface{}{objects.ReplicationController, objects.Deployment} {
+		data, err := EncodeObject(object, "redis", sc)
+		assert.Nil(t, err)
+
+		decoded := struct {
+			Spec struct {
+				Template struct {
+					Spec struct {
+						Containers []struct {
+							ReadinessProbe ProbeTuning
+							LivenessProbe  ProbeTuning
+						}
+					}
+				}
+			}
+		}{}
+		assert.Nil(t, json.Unmarshal(data, &decoded))
+		container := decoded.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, ProbeTuning{PeriodSeconds: 5, SuccessThreshold: 2}, container.ReadinessProbe)
+		assert.Equal(t, ProbeTuning{FailureThreshold: 3}, container.LivenessProbe)
+	}
+
+	// Objects without tunings are encoded as is.
+	data, err := EncodeObject(objects.Service, "redis", &project.ServiceConfig{Image: "redis"})
+	assert.Nil(t, err)
+	expected, _ := json.Marshal(objects.Service)
+	assert.Equal(t, expected, data)
+}
@@ -0,0 +1,126 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/libcompose/project"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// DefaultRegistry is the registry of the images whose name has no registry host.
+const DefaultRegistry = "index.docker.io"
+
+// dockercfgEntry is an entry of the .dockercfg format used by pull secrets.
+type dockercfgEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Auth     string `json:"auth"`
+}
+
+// ImageRegistry returns the registry host of an image, DefaultRegistry for
+// images of the Docker Hub.
+func ImageRegistry(image string) string {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return DefaultRegistry
+	}
+
+	host := image[:i]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return DefaultRegistry
+	}
+	if host == "docker.io" {
+		return DefaultRegistry
+	}
+
+	return host
+}
+
+// normalizeRegistry strips the scheme and path of a registry address of the
+// docker configuration, like https://index.docker.io/v1/.
+func normalizeRegistry(address string) string {
+	address = strings.TrimPrefix(address, "https://")
+	address = strings.TrimPrefix(address, "http://")
+	if i := strings.Index(address, "/"); i >= 0 {
+		address = address[:i]
+	}
+	if address == "docker.io" {
+		return DefaultRegistry
+	}
+	return address
+}
+
+// PullSecret creates a kubernetes.io/dockercfg secret holding the credentials
+// of the docker configuration for the registries used by the services of the
+// project. It returns nil if none of these registries has credentials, along
+// with the services whose image is pulled from a registry of the secret.
+func PullSecret(name string, p *project.Project, config *cliconfig.ConfigFile) (*api.Secret, []string, error) {
+	auths := map[string]cliconfig.AuthConfig{}
+	for address, auth := range config.AuthConfigs {
+		auths[normalizeRegistry(address)] = auth
+	}
+
+	entries := map[string]dockercfgEntry{}
+	services := []string{}
+
+	for serviceName, c := range p.Configs {
+		if c.Image == "" {
+			continue
+		}
+
+		registry := ImageRegistry(c.Image)
+		auth, ok := auths[registry]
+		if !ok {
+			continue
+		}
+
+		services = append(services, serviceName)
+		entries[registry] = dockercfgEntry{
+			Username: auth.Username,
+			Password: auth.Password,
+			Email:    auth.Email,
+			Auth:     cliconfig.EncodeAuth(&auth),
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, nil, nil
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret := &api.Secret{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: api.ObjectMeta{
+			Name:   name,
+			Labels: SharedLabels(p.Name, name),
+		},
+		Type: api.SecretTypeDockercfg,
+		Data: map[string][]byte{
+			api.DockerConfigKey: data,
+		},
+	}
+
+	return secret, services, nil
+}
+
+// SetPullSecret references the secret from the pod template.
+func SetPullSecret(template *api.PodTemplateSpec, secret string) {
+	for _, ref := range template.Spec.ImagePullSecrets {
+		if ref.Name == secret {
+			return
+		}
+	}
+	template.Spec.ImagePullSecrets = append(template.Spec.ImagePullSecrets, api.LocalObjectReference{Name: secret})
+}
@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestImageRegistry(t *testing.T) {
+	for image, registry := range map[string]string{
+		"nginx":                             DefaultRegistry,
+		"library/nginx:1.9":                 DefaultRegistry,
+		"docker.io/library/nginx":           DefaultRegistry,
+		"registry.example.com/team/web:1.0": "registry.example.com",
+		"localhost:5000/web":                "localhost:5000",
+		"localhost/web":                     "localhost",
+	} {
+		assert.Equal(t, registry, ImageRegistry(image), image)
+	}
+}
+
+func TestPullSecret(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Image = "registry.example.com/team/web:1.0"
+	p.Configs["api"] = &project.ServiceConfig{Image: "registry.example.com/team/api"}
+
+	config := &cliconfig.ConfigFile{
+		AuthConfigs: map[string]cliconfig.AuthConfig{
+			"https://registry.example.com/v1/": {Username: "user", Password: "secret", Email: "user@example.com"},
+			"other.example.com":                {Username: "other", Password: "secret"},
+		},
+	}
+
+	secret, services, err := PullSecret("demo-registry", p, config)
+	assert.Nil(t, err)
+	sort.Strings(services)
+	assert.Equal(t, []string{"api", "web"}, services)
+	assert.Equal(t, api.SecretTypeDockercfg, secret.Type)
+	assert.Equal(t, "demo", secret.Labels[PROJECT.Str()])
+	assert.Equal(t, "demo-registry", secret.Labels[SERVICE.Str()])
+
+	entries := map[string]dockercfgEntry{}
+	assert.Nil(t, json.Unmarshal(secret.Data[api.DockerConfigKey], &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "user", entries["registry.example.com"].Username)
+	assert.Equal(t, "dXNlcjpzZWNyZXQ=", entries["registry.example.com"].Auth)
+}
+
+func TestPullSecretNoCredentials(t *testing.T) {
+	secret, services, err := PullSecret("demo-registry", newTestProject(), &cliconfig.ConfigFile{})
+	assert.Nil(t, err)
+	assert.Nil(t, secret)
+	assert.Nil(t, services)
+}
+
+func TestSetPullSecret(t *testing.T) {
+	template := &api.PodTemplateSpec{}
+	SetPullSecret(template, "demo-registry")
+	SetPullSecret(template, "demo-registry")
+	assert.Equal(t, []api.LocalObjectReference{{Name: "demo-registry"}}, template.Spec.ImagePullSecrets)
+}
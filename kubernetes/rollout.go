@@ -0,0 +1,187 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// RevisionAnnotation is set by the deployment controller on the replica sets
+// of a deployment.
+const RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// PodTemplateHashLabel is the default unique label key of the deployments. The
+// deployment controller labels the replication controllers it creates, and
+// their pods, with the hash of the pod template.
+const PodTemplateHashLabel = "deployment.kubernetes.io/podTemplateHash"
+
+// IsDeploymentController returns true if the replication controller was
+// created by the controller of a deployment, which manages its replicas.
+func IsDeploymentController(rc *api.ReplicationController) bool {
+	_, ok := rc.Labels[PodTemplateHashLabel]
+	return ok
+}
+
+// RolloutStatus holds the progress of the rollout of a controller. Pods are
+// updated when they carry the configuration hash of the controller template.
+type RolloutStatus struct {
+	Desired   int
+	Current   int
+	Updated   int
+	Available int
+}
+
+// NewRolloutStatus computes the rollout status of a controller with the
+// specified desired replicas and template hash from its pods.
+func NewRolloutStatus(pods []api.Pod, hash string, desired int) RolloutStatus {
+	status := RolloutStatus{Desired: desired}
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		status.Current++
+		if pod.Labels[HASH.Str()] != hash {
+			continue
+		}
+		status.Updated++
+		if api.IsPodReady(pod) {
+			status.Available++
+		}
+	}
+
+	return status
+}
+
+// Done returns true once all the desired pods are updated and available, and
+// the pods of the previous configuration are gone.
+func (s RolloutStatus) Done() bool {
+	return s.Updated == s.Desired && s.Available == s.Desired && s.Current == s.Desired
+}
+
+// Data returns the status as project event data.
+func (s RolloutStatus) Data() map[string]string {
+	return map[string]string{
+		"desired":   strconv.Itoa(s.Desired),
+		"current":   strconv.Itoa(s.Current),
+		"updated":   strconv.Itoa(s.Updated),
+		"available": strconv.Itoa(s.Available),
+	}
+}
+
+// Revision is an entry of the rollout history of a deployment.
+type Revision struct {
+	Revision   int64
+	ReplicaSet string
+	Replicas   int
+	Hash       string
+}
+
+// RevisionHistory returns the revisions of the deployments matching the
+// selector, oldest first, from their replica sets. Replica sets are not part
+// of the vendored API types, so they are decoded from the raw response.
+// Servers without replica sets yield an empty history.
+func RevisionHistory(client *client.Client, selector labels.Selector) ([]Revision, error) {
+	data, err := client.ExtensionsClient.Get().Namespace(api.NamespaceDefault).Resource("replicasets").LabelsSelectorParam(selector).Do().Raw()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return parseRevisionHistory(data)
+}
+
+func parseRevisionHistory(data []byte) ([]Revision, error) {
+	list := struct {
+		Items []struct {
+			Metadata struct {
+				Name        string            `json:"name"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+			Spec struct {
+				Replicas int `json:"replicas"`
+				Template struct {
+					Metadata struct {
+						Labels map[string]string `json:"labels"`
+					} `json:"metadata"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	revisions := []Revision{}
+	for _, item := range list.Items {
+		value, ok := item.Metadata.Annotations[RevisionAnnotation]
+		if !ok {
+			continue
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid revision %s of replica set %s", value, item.Metadata.Name)
+		}
+		revisions = append(revisions, Revision{
+			Revision:   number,
+			ReplicaSet: item.Metadata.Name,
+			Replicas:   item.Spec.Replicas,
+			Hash:       item.Spec.Template.Metadata.Labels[HASH.Str()],
+		})
+	}
+
+	sort.Sort(revisionsByNumber(revisions))
+	return revisions, nil
+}
+
+type revisionsByNumber []Revision
+
+func (r revisionsByNumber) Len() int           { return len(r) }
+func (r revisionsByNumber) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r revisionsByNumber) Less(i, j int) bool { return r[i].Revision < r[j].Revision }
+
+// deploymentRollback mirrors the extensions/v1beta1 DeploymentRollback, which
+// is not part of the vendored API types.
+type deploymentRollback struct {
+	unversioned.TypeMeta `json:",inline"`
+	Name                 string `json:"name"`
+	RollbackTo           struct {
+		Revision int64 `json:"revision"`
+	} `json:"rollbackTo"`
+}
+
+// RollbackDeployment rolls a deployment back to the specified revision, or to
+// the previous one when revision is 0.
+func RollbackDeployment(client *client.Client, name string, revision int64) error {
+	rollback := deploymentRollback{
+		TypeMeta: unversioned.TypeMeta{
+			Kind:       "DeploymentRollback",
+			APIVersion: "extensions/v1beta1",
+		},
+		Name: name,
+	}
+	rollback.RollbackTo.Revision = revision
+
+	data, err := json.Marshal(rollback)
+	if err != nil {
+		return err
+	}
+
+	return client.ExtensionsClient.Post().
+		Namespace(api.NamespaceDefault).
+		Resource("deployments").
+		Name(name).
+		SubResource("rollback").
+		Body(data).
+		Do().
+		Error()
+}
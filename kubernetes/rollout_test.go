@@ -0,0 +1,74 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+func rolloutPod(hash string, ready bool) api.Pod {
+	condition := api.ConditionFalse
+	if ready {
+		condition = api.ConditionTrue
+	}
+	return api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Labels: map[string]string{HASH.Str(): hash},
+		},
+		Status: api.PodStatus{
+			Conditions: []api.PodCondition{{Type: api.PodReady, Status: condition}},
+		},
+	}
+}
+
+func TestRolloutStatus(t *testing.T) {
+	terminating := rolloutPod("old", true)
+	now := unversioned.Now()
+	terminating.DeletionTimestamp = &now
+
+	pods := []api.Pod{
+		rolloutPod("old", true),
+		rolloutPod("new", true),
+		rolloutPod("new", false),
+		terminating,
+	}
+
+	status := NewRolloutStatus(pods, "new", 2)
+	assert.Equal(t, RolloutStatus{Desired: 2, Current: 3, Updated: 2, Available: 1}, status)
+	assert.False(t, status.Done())
+	assert.Equal(t, "1", status.Data()["available"])
+
+	status = NewRolloutStatus([]api.Pod{rolloutPod("new", true), rolloutPod("new", true)}, "new", 2)
+	assert.True(t, status.Done())
+}
+
+func TestParseRevisionHistory(t *testing.T) {
+	data := []byte(`{"items": [
+		{"metadata": {"name": "web-2", "annotations": {"deployment.kubernetes.io/revision": "2"}},
+		 "spec": {"replicas": 3, "template": {"metadata": {"labels": {"kompose.config-hash": "def"}}}}},
+		{"metadata": {"name": "web-1", "annotations": {"deployment.kubernetes.io/revision": "1"}},
+		 "spec": {"replicas": 0, "template": {"metadata": {"labels": {"kompose.config-hash": "abc"}}}}},
+		{"metadata": {"name": "other"}, "spec": {"replicas": 1}}
+	]}`)
+
+	revisions, err := parseRevisionHistory(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []Revision{
+		{Revision: 1, ReplicaSet: "web-1", Replicas: 0, Hash: "abc"},
+		{Revision: 2, ReplicaSet: "web-2", Replicas: 3, Hash: "def"},
+	}, revisions)
+
+	_, err = parseRevisionHistory([]byte(`{"items": [{"metadata": {"name": "web-1", "annotations": {"deployment.kubernetes.io/revision": "x"}}}]}`))
+	assert.NotNil(t, err)
+}
+
+func TestIsDeploymentController(t *testing.T) {
+	rc := &api.ReplicationController{ObjectMeta: api.ObjectMeta{Labels: map[string]string{"service": "web"}}}
+	assert.False(t, IsDeploymentController(rc))
+
+	rc.Labels[PodTemplateHashLabel] = "1234"
+	assert.True(t, IsDeploymentController(rc))
+}
@@ -0,0 +1,446 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/utils"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// PausedReplicasAnnotation holds the replicas of a paused replication
+// controller, so that unpausing it restores its scale.
+const PausedReplicasAnnotation = "kompose.paused-replicas"
+
+// Service is a project.Service implementation running the service as a
+// replication controller (and a kubernetes service when it exposes ports).
+type Service struct {
+	name          string
+	serviceConfig *project.ServiceConfig
+	context       *Context
+}
+
+// NewService creates a service
+func NewService(name string, serviceConfig *project.ServiceConfig, context *Context) *Service {
+	return &Service{
+		name:          name,
+		serviceConfig: serviceConfig,
+		context:       context,
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return s.name
+}
+
+// Config returns the configuration of the service (project.ServiceConfig).
+func (s *Service) Config() *project.ServiceConfig {
+	return s.serviceConfig
+}
+
+// DependentServices returns the dependent services (as an array of ServiceRelationship) of the service.
+func (s *Service) DependentServices() []project.ServiceRelationship {
+	return project.DefaultDependentServices(s.context.Project, s)
+}
+
+// Build implements Service.Build. Images are not built on the cluster, so
+// services must reference an image.
+func (s *Service) Build() error {
+	if s.serviceConfig.Image == "" {
+		return fmt.Errorf("Service %s has no image, building is not supported on kubernetes", s.name)
+	}
+	return nil
+}
+
+// Pull implements Service.Pull. Images are pulled by the cluster nodes.
+func (s *Service) Pull() error {
+	return s.Build()
+}
+
+// Create implements Service.Create. It creates the replication controller,
+// without replicas, and the kubernetes service of the compose service.
+func (s *Service) Create() error {
+	_, err := s.apply(0)
+	return err
+}
+
+// Up implements Service.Up. It creates or updates the objects of the service
+// and makes sure at least one replica is running.
+func (s *Service) Up() error {
+	_, err := s.apply(1)
+	return err
+}
+
+// Start implements Service.Start. It scales a stopped service back to one replica.
+func (s *Service) Start() error {
+	rc, err := s.controller()
+	if err != nil {
+		return err
+	}
+	if rc.Spec.Replicas > 0 {
+		return nil
+	}
+	return s.Scale(1)
+}
+
+// Down implements Service.Down. It scales the service down to zero replicas.
+func (s *Service) Down() error {
+	return s.Scale(0)
+}
+
+// Restart implements Service.Restart. It deletes the pods of the service so
+// the replication controller recreates them.
+func (s *Service) Restart() error {
+	return s.deletePods(nil)
+}
+
+// Kill implements Service.Kill. It scales the service down and deletes its
+// pods without grace period.
+func (s *Service) Kill() error {
+	if err := s.Down(); err != nil {
+		return err
+	}
+
+	grace := int64(0)
+	return s.deletePods(&api.DeleteOptions{GracePeriodSeconds: &grace})
+}
+
+// Delete implements Service.Delete. It scales the service down, waits for its
+// pods to terminate and removes the replication controller and kubernetes service.
+// Objects of the same name belonging to another project are left alone.
+func (s *Service) Delete() error {
+	// Down fails on a replication controller of another project.
+	if err := s.Down(); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if err := WaitForPodsGone(s.context.Client, s.selector(), s.timeout()); err != nil {
+		return fmt.Errorf("Pods of service %s did not terminate: %v", s.name, err)
+	}
+
+	if err := s.context.Client.ReplicationControllers(api.NamespaceDefault).Delete(s.name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	services := s.context.Client.Services(api.NamespaceDefault)
+	existing, err := services.Get(s.name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := s.checkProject("Service", &existing.ObjectMeta); err != nil {
+		return err
+	}
+
+	if err := services.Delete(s.name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Log implements Service.Log. It streams the logs of each pod of the service.
+func (s *Service) Log() error {
+	pods, err := s.pods()
+	if err != nil {
+		return err
+	}
+
+	tasks := utils.InParallel{}
+	for _, pod := range pods {
+		l := s.context.LoggerFactory.Create(pod.Name)
+		name := pod.Name
+		tasks.Add(func() error {
+			return StreamPodLogs(s.context.Client, name, s.name, LogOptions{Follow: s.context.Log, TailLines: -1}, l)
+		})
+	}
+
+	return tasks.Wait()
+}
+
+// Scale implements Service.Scale. It updates the replicas of the replication controller.
+func (s *Service) Scale(scale int) error {
+	rc, err := s.controller()
+	if err != nil {
+		return err
+	}
+
+	if rc.Spec.Replicas == scale {
+		return nil
+	}
+
+	rc.Spec.Replicas = scale
+	_, err = s.updateController(rc)
+	return err
+}
+
+// Pause implements Service.Pause. Pods cannot be frozen, so the service is
+// scaled down and its replicas are recorded to be restored by Unpause.
+func (s *Service) Pause() error {
+	rc, err := s.controller()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := rc.Annotations[PausedReplicasAnnotation]; ok {
+		return nil
+	}
+
+	if rc.Annotations == nil {
+		rc.Annotations = map[string]string{}
+	}
+	rc.Annotations[PausedReplicasAnnotation] = strconv.Itoa(rc.Spec.Replicas)
+	rc.Spec.Replicas = 0
+
+	_, err = s.updateController(rc)
+	return err
+}
+
+// Unpause implements Service.Unpause. It restores the replicas recorded by Pause.
+func (s *Service) Unpause() error {
+	rc, err := s.controller()
+	if err != nil {
+		return err
+	}
+
+	value, ok := rc.Annotations[PausedReplicasAnnotation]
+	if !ok {
+		return nil
+	}
+
+	replicas, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Invalid %s annotation on service %s: %s", PausedReplicasAnnotation, s.name, value)
+	}
+
+	delete(rc.Annotations, PausedReplicasAnnotation)
+	rc.Spec.Replicas = replicas
+
+	_, err = s.updateController(rc)
+	return err
+}
+
+// Info implements Service.Info. It returns one line per pod of the service.
+func (s *Service) Info(qFlag bool) (project.InfoSet, error) {
+	result := project.InfoSet{}
+	pods, err := s.pods()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pods {
+		status := NewPodStatus(&pods[i])
+		if qFlag {
+			result = append(result, project.Info{
+				{Key: "Id", Value: string(pods[i].UID)},
+			})
+			continue
+		}
+		result = append(result, project.Info{
+			{Key: "Name", Value: status.Name},
+			{Key: "Phase", Value: status.Phase},
+			{Key: "Ready", Value: strconv.FormatBool(status.Ready)},
+			{Key: "Restarts", Value: strconv.Itoa(status.Restarts)},
+			{Key: "IP", Value: pods[i].Status.PodIP},
+		})
+	}
+
+	return result, nil
+}
+
+// Containers implements Service.Containers. It returns the pods of the service.
+func (s *Service) Containers() ([]project.Container, error) {
+	result := []project.Container{}
+	pods, err := s.pods()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pods {
+		result = append(result, NewContainer(s, &pods[i]))
+	}
+
+	return result, nil
+}
+
+// apply creates the objects of the service, or updates them when their
+// configuration hash changed. The controller gets at least minReplicas.
+func (s *Service) apply(minReplicas int) (*api.ReplicationController, error) {
+	objects, err := ConvertToAPI(s.context.Project.Name, s.name, s.serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// A replication controller would run the job over and over.
+	if objects.Job != nil {
+		logrus.Warnf("Service %s is a job and is not run by kompose, generate it with kompose convert", s.name)
+		return nil, nil
+	}
+
+	rc, err := s.controller()
+	if errors.IsNotFound(err) {
+		objects.ReplicationController.Spec.Replicas = minReplicas
+		logrus.Infof("Creating replication controller %s", s.name)
+		rc, err = s.createController(objects.ReplicationController)
+		if err != nil {
+			return nil, err
+		}
+		return rc, s.applyService(objects.Service)
+	} else if err != nil {
+		return nil, err
+	}
+
+	outOfSync := rc.Labels[HASH.Str()] != objects.ReplicationController.Labels[HASH.Str()]
+	recreate := !s.context.NoRecreate && (outOfSync || s.context.ForceRecreate)
+
+	if recreate {
+		logrus.Infof("Recreating %s", s.name)
+		objects.ReplicationController.ResourceVersion = rc.ResourceVersion
+		objects.ReplicationController.Spec.Replicas = rc.Spec.Replicas
+		rc, err = s.updateController(objects.ReplicationController)
+		if err != nil {
+			return nil, err
+		}
+		// Replication controllers do not roll their pods on template changes.
+		if err := s.deletePods(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if rc.Spec.Replicas < minReplicas {
+		rc.Spec.Replicas = minReplicas
+		if rc, err = s.updateController(rc); err != nil {
+			return nil, err
+		}
+	}
+
+	return rc, s.applyService(objects.Service)
+}
+
+// applyService creates or updates the kubernetes service, if the compose
+// service exposes any port.
+func (s *Service) applyService(sc *api.Service) error {
+	if len(sc.Spec.Ports) == 0 {
+		return nil
+	}
+
+	services := s.context.Client.Services(api.NamespaceDefault)
+	existing, err := services.Get(s.name)
+	if errors.IsNotFound(err) {
+		logrus.Infof("Creating service %s", s.name)
+		_, err = services.Create(sc)
+		return err
+	} else if err != nil {
+		return err
+	}
+	if err := s.checkProject("Service", &existing.ObjectMeta); err != nil {
+		return err
+	}
+
+	if existing.Labels[HASH.Str()] == sc.Labels[HASH.Str()] {
+		return nil
+	}
+
+	sc.ResourceVersion = existing.ResourceVersion
+	sc.Spec.ClusterIP = existing.Spec.ClusterIP
+	_, err = services.Update(sc)
+	return err
+}
+
+// createController creates the replication controller of the service.
+func (s *Service) createController(rc *api.ReplicationController) (*api.ReplicationController, error) {
+	return s.writeController(rc, true)
+}
+
+// updateController updates the replication controller of the service.
+func (s *Service) updateController(rc *api.ReplicationController) (*api.ReplicationController, error) {
+	return s.writeController(rc, false)
+}
+
+// writeController sends the replication controller as JSON, with the probe
+// fields api.Probe lacks, which the typed client would drop.
+func (s *Service) writeController(rc *api.ReplicationController, create bool) (*api.ReplicationController, error) {
+	rc.Kind, rc.APIVersion = "ReplicationController", "v1"
+
+	data, err := json.Marshal(rc)
+	// The controller of another configuration would get the probes of this one.
+	if rc.Labels[HASH.Str()] == ServiceLabels(s.context.Project.Name, s.name, s.serviceConfig)[HASH.Str()] {
+		data, err = EncodeObject(rc, s.name, s.serviceConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	request := s.context.Client.Post()
+	if !create {
+		request = s.context.Client.Put().Name(rc.Name)
+	}
+
+	result := &api.ReplicationController{}
+	err = request.Namespace(api.NamespaceDefault).Resource("replicationcontrollers").Body(data).Do().Into(result)
+	return result, err
+}
+
+// controller returns the replication controller of the service, failing when
+// the one of that name belongs to another project.
+func (s *Service) controller() (*api.ReplicationController, error) {
+	rc, err := s.context.Client.ReplicationControllers(api.NamespaceDefault).Get(s.name)
+	if err != nil {
+		return nil, err
+	}
+	return rc, s.checkProject("Replication controller", &rc.ObjectMeta)
+}
+
+// checkProject makes sure an existing object named after the service belongs
+// to the project, so that objects of other projects, or created by hand, are
+// never updated or removed.
+func (s *Service) checkProject(kind string, meta *api.ObjectMeta) error {
+	if name := meta.Labels[PROJECT.Str()]; name != s.context.Project.Name {
+		return fmt.Errorf("%s %s does not belong to project %s", kind, meta.Name, s.context.Project.Name)
+	}
+	return nil
+}
+
+func (s *Service) pods() ([]api.Pod, error) {
+	pods, err := s.context.Client.Pods(api.NamespaceDefault).List(s.selector(), fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func (s *Service) deletePods(options *api.DeleteOptions) error {
+	pods, err := s.pods()
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		if err := s.context.Client.Pods(api.NamespaceDefault).Delete(pod.Name, options); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) selector() labels.Selector {
+	return ServiceSelector(s.context.Project, s.name)
+}
+
+func (s *Service) timeout() time.Duration {
+	if s.context.Timeout == 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(s.context.Timeout) * time.Second
+}
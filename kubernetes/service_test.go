@@ -0,0 +1,147 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/kubernetes/fake"
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func newFakeProject(t *testing.T, s *fake.Server, compose string) (*project.Project, *Context) {
+	context := &Context{
+		Context: project.Context{
+			ComposeBytes: []byte(compose),
+			ProjectName:  "demo",
+		},
+		Client: s.Client(),
+	}
+	p, err := NewProject(context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, context
+}
+
+func TestServiceLifecycle(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+
+	p, context := newFakeProject(t, s, `
+web:
+  image: nginx:1.9
+  ports:
+  - "80"
+  links:
+  - redis
+redis:
+  image: redis:3
+`)
+
+	assert.Nil(t, p.Up())
+	assert.Equal(t, []string{"redis", "web"}, s.Names("replicationcontrollers"))
+	// Services are only created for the compose services exposing ports.
+	assert.Equal(t, []string{"web"}, s.Names("services"))
+	assert.Len(t, s.Names("pods"), 2)
+
+	assert.Nil(t, NewService("web", p.Configs["web"], context).Scale(3))
+	assert.Len(t, s.Names("pods"), 4)
+
+	// Restarted pods are replaced by the controller.
+	pods := s.Names("pods")
+	assert.Nil(t, p.Restart("web"))
+	assert.Len(t, s.Names("pods"), 4)
+	assert.NotEqual(t, pods, s.Names("pods"))
+
+	assert.Nil(t, p.Pause("web"))
+	rc := api.ReplicationController{}
+	assert.Nil(t, s.Get("replicationcontrollers", "web", &rc))
+	assert.Equal(t, 0, rc.Spec.Replicas)
+	assert.Equal(t, "3", rc.Annotations[PausedReplicasAnnotation])
+
+	assert.Nil(t, p.Unpause("web"))
+	assert.Nil(t, s.Get("replicationcontrollers", "web", &rc))
+	assert.Equal(t, 3, rc.Spec.Replicas)
+
+	// Up leaves the services whose configuration did not change alone.
+	before := len(s.Requests())
+	assert.Nil(t, p.Up())
+	for _, request := range s.Requests()[before:] {
+		assert.NotContains(t, request, "PUT", request)
+	}
+
+	assert.Nil(t, p.Delete())
+	assert.Empty(t, s.Names("replicationcontrollers"))
+	assert.Empty(t, s.Names("services"))
+	assert.Empty(t, s.Names("pods"))
+}
+
+func TestServiceOfAnotherProject(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+
+	other := map[string]string{PROJECT.Str(): "other"}
+	assert.Nil(t, s.Create("replicationcontrollers", &api.ReplicationController{
+		ObjectMeta: api.ObjectMeta{Name: "web", Labels: other},
+		Spec:       api.ReplicationControllerSpec{Replicas: 1},
+	}))
+	assert.Nil(t, s.Create("services", &api.Service{ObjectMeta: api.ObjectMeta{Name: "redis", Labels: other}}))
+
+	p, _ := newFakeProject(t, s, `
+web:
+  image: nginx:1.9
+redis:
+  image: redis:3
+  ports:
+  - "6379"
+`)
+
+	err := p.Up("web")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Replication controller web does not belong to project demo")
+	err = p.Up("redis")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Service redis does not belong to project demo")
+
+	err = p.Delete()
+	assert.NotNil(t, err)
+	// The objects of the other project are kept.
+	rc := api.ReplicationController{}
+	assert.Nil(t, s.Get("replicationcontrollers", "web", &rc))
+	assert.Equal(t, 1, rc.Spec.Replicas)
+	assert.Equal(t, []string{"redis"}, s.Names("services"))
+}
+
+func TestServiceProbeTunings(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+
+	p, context := newFakeProject(t, s, `
+redis:
+  image: redis:3
+  labels:
+    kompose.probe.liveness: tcp:6379
+    kompose.probe.liveness.period: 20
+    kompose.probe.liveness.threshold: 3
+`)
+
+	decoded := struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						LivenessProbe ProbeTuning
+					}
+				}
+			}
+		}
+	}{}
+
+	// The fields api.Probe lacks are kept when the controller is scaled.
+	assert.Nil(t, p.Up())
+	assert.Nil(t, NewService("redis", p.Configs["redis"], context).Scale(2))
+	assert.Nil(t, s.Get("replicationcontrollers", "redis", &decoded))
+	assert.Equal(t, ProbeTuning{PeriodSeconds: 20, FailureThreshold: 3}, decoded.Spec.Template.Spec.Containers[0].LivenessProbe)
+}
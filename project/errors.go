@@ -0,0 +1,149 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrServiceNotFound is the cause of the errors about services which are not
+// defined in the project.
+var ErrServiceNotFound = errors.New("No such service")
+
+// ServiceError is the error of an action on a service.
+type ServiceError struct {
+	Service string
+	Err     error
+}
+
+// NewServiceNotFound returns the error of a service missing from the project.
+func NewServiceNotFound(name string) *ServiceError {
+	return &ServiceError{Service: name, Err: ErrServiceNotFound}
+}
+
+func (e *ServiceError) Error() string {
+	if e.Err == ErrServiceNotFound {
+		return fmt.Sprintf("%v: %s", e.Err, e.Service)
+	}
+	// Validation errors already name the service.
+	if _, ok := e.Err.(*ValidationError); ok {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("Service %s: %v", e.Service, e.Err)
+}
+
+// ValidationError is an invalid value of the configuration of a service. Field
+// is the path of the value within the service, map keys being in brackets,
+// like labels[kompose.job].
+type ValidationError struct {
+	Service string
+	Field   string
+	Message string
+}
+
+// NewValidationError returns the error of an invalid field of a service, with
+// a message formatted according to the format specifier.
+func NewValidationError(service, field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Service: service,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// LabelField returns the field path of a label.
+func LabelField(label string) string {
+	return "labels[" + label + "]"
+}
+
+// Path returns the path of the field from the root of the compose file, like
+// web.labels[kompose.job].
+func (e *ValidationError) Path() string {
+	return e.Service + "." + e.Field
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s of service %s: %s", e.Field, e.Service, e.Message)
+}
+
+// ActionError is the error of an action run on several services, holding the
+// services it succeeded on along with the errors of the other ones.
+type ActionError struct {
+	Succeeded []string
+	Failed    []*ServiceError
+}
+
+// NewActionError returns the error of an action from the result of each
+// service, nil if it succeeded on all of them.
+func NewActionError(results map[string]error) error {
+	e := &ActionError{}
+	for name, err := range results {
+		if err == nil {
+			e.Succeeded = append(e.Succeeded, name)
+		} else if serviceError, ok := err.(*ServiceError); ok && serviceError.Service == name {
+			e.Failed = append(e.Failed, serviceError)
+		} else {
+			e.Failed = append(e.Failed, &ServiceError{Service: name, Err: err})
+		}
+	}
+
+	if len(e.Failed) == 0 {
+		return nil
+	}
+
+	sort.Strings(e.Succeeded)
+	sort.Sort(serviceErrorsByName(e.Failed))
+	return e
+}
+
+func (e *ActionError) Error() string {
+	messages := []string{}
+	for _, err := range e.Failed {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Summary returns one row per service with the result of the action.
+func (e *ActionError) Summary() InfoSet {
+	result := InfoSet{}
+	for _, name := range e.Succeeded {
+		result = append(result, Info{
+			{Key: "Service", Value: name},
+			{Key: "Result", Value: "done"},
+			{Key: "Error", Value: ""},
+		})
+	}
+	for _, err := range e.Failed {
+		result = append(result, Info{
+			{Key: "Service", Value: err.Service},
+			{Key: "Result", Value: "failed"},
+			{Key: "Error", Value: fmt.Sprint(Cause(err))},
+		})
+	}
+	return result
+}
+
+type serviceErrorsByName []*ServiceError
+
+func (e serviceErrorsByName) Len() int           { return len(e) }
+func (e serviceErrorsByName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e serviceErrorsByName) Less(i, j int) bool { return e[i].Service < e[j].Service }
+
+// Cause returns the underlying error of the error of an action on a service.
+func Cause(err error) error {
+	for {
+		serviceError, ok := err.(*ServiceError)
+		if !ok {
+			return err
+		}
+		err = serviceError.Err
+	}
+}
+
+// IsServiceNotFound returns true if the error is about a service which is not
+// defined in the project.
+func IsServiceNotFound(err error) bool {
+	return Cause(err) == ErrServiceNotFound
+}
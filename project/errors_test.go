@@ -0,0 +1,105 @@
+package project
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	err := NewValidationError("web", LabelField("kompose.job"), "must be a boolean, got %s", "maybe")
+
+	if err.Path() != "web.labels[kompose.job]" {
+		t.Fatalf("Unexpected path: %s", err.Path())
+	}
+	if err.Error() != "Invalid labels[kompose.job] of service web: must be a boolean, got maybe" {
+		t.Fatalf("Unexpected message: %s", err.Error())
+	}
+}
+
+func TestNewActionError(t *testing.T) {
+	if err := NewActionError(map[string]error{"web": nil, "redis": nil}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	failure := errors.New("Failed")
+	err := NewActionError(map[string]error{
+		"web":   failure,
+		"redis": nil,
+		"db":    NewServiceNotFound("db"),
+		"cache": nil,
+	})
+	actionError, ok := err.(*ActionError)
+	if !ok {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	if !reflect.DeepEqual([]string{"cache", "redis"}, actionError.Succeeded) {
+		t.Fatalf("Unexpected succeeded services: %v", actionError.Succeeded)
+	}
+	if len(actionError.Failed) != 2 || !IsServiceNotFound(actionError.Failed[0]) || Cause(actionError.Failed[1]) != failure {
+		t.Fatalf("Unexpected failed services: %v", actionError.Failed)
+	}
+	if err.Error() != "No such service: db; Service web: Failed" {
+		t.Fatalf("Unexpected message: %s", err.Error())
+	}
+
+	summary := actionError.Summary()
+	if len(summary) != 4 {
+		t.Fatalf("Unexpected summary: %v", summary)
+	}
+	if !reflect.DeepEqual(Info{{"Service", "web"}, {"Result", "failed"}, {"Error", "Failed"}}, summary[3]) {
+		t.Fatalf("Unexpected summary of web: %v", summary[3])
+	}
+}
+
+type failingServiceFactory struct {
+	TestServiceFactory
+	failing string
+}
+
+type failingService struct {
+	TestService
+}
+
+func (s *failingService) Create() error {
+	return errors.New("Failed to create " + s.name)
+}
+
+func (f *failingServiceFactory) Create(project *Project, name string, serviceConfig *ServiceConfig) (Service, error) {
+	if name == f.failing {
+		return &failingService{TestService{factory: &f.TestServiceFactory, name: name, config: serviceConfig}}, nil
+	}
+	return f.TestServiceFactory.Create(project, name, serviceConfig)
+}
+
+func TestProjectActionError(t *testing.T) {
+	factory := &failingServiceFactory{
+		TestServiceFactory: TestServiceFactory{Counts: map[string]int{}},
+		failing:            "bar",
+	}
+
+	p := NewProject(&Context{
+		ServiceFactory: factory,
+	})
+	p.Configs = map[string]*ServiceConfig{
+		"foo": {},
+		"bar": {},
+	}
+
+	err := p.Create()
+	actionError, ok := err.(*ActionError)
+	if !ok {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if !reflect.DeepEqual([]string{"foo"}, actionError.Succeeded) || len(actionError.Failed) != 1 || actionError.Failed[0].Service != "bar" {
+		t.Fatalf("Unexpected result: %v %v", actionError.Succeeded, actionError.Failed)
+	}
+	if factory.Counts["foo.create"] != 1 {
+		t.Fatal("Failed to create the other services")
+	}
+
+	if err := p.Create("baz"); !IsServiceNotFound(err) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
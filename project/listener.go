@@ -8,22 +8,26 @@ import (
 
 var (
 	infoEvents = map[EventType]bool{
-		EventProjectDeleteDone:   true,
-		EventProjectDeleteStart:  true,
-		EventProjectDownDone:     true,
-		EventProjectDownStart:    true,
-		EventProjectRestartDone:  true,
-		EventProjectRestartStart: true,
-		EventProjectUpDone:       true,
-		EventProjectUpStart:      true,
-		EventServiceDeleteStart:  true,
-		EventServiceDelete:       true,
-		EventServiceDownStart:    true,
-		EventServiceDown:         true,
-		EventServiceRestartStart: true,
-		EventServiceRestart:      true,
-		EventServiceUpStart:      true,
-		EventServiceUp:           true,
+		EventProjectDeleteDone:      true,
+		EventProjectDeleteStart:     true,
+		EventProjectDownDone:        true,
+		EventProjectDownStart:       true,
+		EventProjectRestartDone:     true,
+		EventProjectRestartStart:    true,
+		EventProjectUpDone:          true,
+		EventProjectUpStart:         true,
+		EventServiceDeleteStart:     true,
+		EventServiceDelete:          true,
+		EventServiceDownStart:       true,
+		EventServiceDown:            true,
+		EventServiceRestartStart:    true,
+		EventServiceRestart:         true,
+		EventServiceUpStart:         true,
+		EventServiceUp:              true,
+		EventServiceRolloutProgress: true,
+		EventServiceRollout:         true,
+		EventServiceRollbackStart:   true,
+		EventServiceRollback:        true,
 	}
 )
 
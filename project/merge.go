@@ -31,9 +31,32 @@ var (
 type rawService map[string]interface{}
 type rawServiceMap map[string]rawService
 
+// recordingLookup is a ConfigLookup recording the files it resolves.
+type recordingLookup struct {
+	lookup ConfigLookup
+	files  []string
+}
+
+func (r *recordingLookup) Lookup(file, relativeTo string) ([]byte, string, error) {
+	bytes, resolved, err := r.lookup.Lookup(file, relativeTo)
+	if resolved != "" {
+		r.files = append(r.files, resolved)
+	}
+	return bytes, resolved, err
+}
+
 func mergeProject(p *Project, bytes []byte) (map[string]*ServiceConfig, error) {
 	configs := make(map[string]*ServiceConfig)
 
+	var configLookup ConfigLookup
+	if p.context.ConfigLookup != nil {
+		recorder := &recordingLookup{lookup: p.context.ConfigLookup}
+		defer func() {
+			p.addFiles(recorder.files...)
+		}()
+		configLookup = recorder
+	}
+
 	datas := make(rawServiceMap)
 	if err := yaml.Unmarshal(bytes, &datas); err != nil {
 		return nil, err
@@ -44,7 +67,7 @@ func mergeProject(p *Project, bytes []byte) (map[string]*ServiceConfig, error) {
 	}
 
 	for name, data := range datas {
-		data, err := parse(p.context.ConfigLookup, p.context.EnvironmentLookup, p.File, data, datas)
+		data, err := parse(configLookup, p.context.EnvironmentLookup, p.File, data, datas)
 		if err != nil {
 			logrus.Errorf("Failed to parse service %s: %v", name, err)
 			return nil, err
@@ -76,12 +76,64 @@ func (p *Project) Parse() error {
 	return nil
 }
 
+// Reload re-reads the compose file and the files it references, replacing the
+// service configurations, then calls the ReloadCallback. Listeners are notified
+// with EventProjectReloadTrigger before and EventProjectReload once reloaded.
+func (p *Project) Reload() error {
+	if p.context.ComposeFile == "-" {
+		return errors.New("Cannot reload a compose file read from stdin")
+	}
+
+	p.Notify(EventProjectReloadTrigger, "", nil)
+
+	p.context.ComposeBytes = nil
+	if err := p.context.readComposeFile(); err != nil {
+		return err
+	}
+
+	configs, files, reload := p.Configs, p.Files, p.reload
+	p.Configs = make(map[string]*ServiceConfig)
+	p.Files = nil
+	p.reload = nil
+
+	if p.context.ComposeBytes != nil {
+		if err := p.Load(p.context.ComposeBytes); err != nil {
+			p.Configs, p.Files, p.reload = configs, files, reload
+			return err
+		}
+	}
+
+	p.Notify(EventProjectReload, "", nil)
+
+	if p.ReloadCallback != nil {
+		return p.ReloadCallback()
+	}
+
+	return nil
+}
+
+// addFiles records files the project is loaded from, once each.
+func (p *Project) addFiles(files ...string) {
+	for _, file := range files {
+		found := false
+		for _, existing := range p.Files {
+			if existing == file {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.Files = append(p.Files, file)
+		}
+	}
+}
+
 // CreateService creates a service with the specified name based. It there
 // is no config in the project for this service, it will return an error.
 func (p *Project) CreateService(name string) (Service, error) {
 	existing, ok := p.Configs[name]
 	if !ok {
-		return nil, fmt.Errorf("Failed to find service: %s", name)
+		return nil, NewServiceNotFound(name)
 	}
 
 	// Copy because we are about to modify the environment
@@ -123,6 +175,10 @@ func (p *Project) AddConfig(name string, config *ServiceConfig) error {
 // Load loads the specified byte array (the composefile content) and adds the
 // service configuration to the project.
 func (p *Project) Load(bytes []byte) error {
+	if p.File != "" && p.File != "." {
+		p.addFiles(p.File)
+	}
+
 	configs := make(map[string]*ServiceConfig)
 	configs, err := mergeProject(p, bytes)
 	if err != nil {
@@ -360,7 +416,7 @@ func (p *Project) traverse(start bool, selected map[string]bool, wrappers map[st
 	// check service name
 	for s := range selected {
 		if wrappers[s] == nil {
-			return errors.New("No such service: " + s)
+			return NewServiceNotFound(s)
 		}
 	}
 
@@ -370,20 +426,20 @@ func (p *Project) traverse(start bool, selected map[string]bool, wrappers map[st
 		p.startService(wrappers, []string{}, selected, launched, wrapper, action, cycleAction)
 	}
 
-	var firstError error
+	results := map[string]error{}
 
 	for _, wrapper := range wrappers {
 		if !isSelected(wrapper, selected) {
 			continue
 		}
-		if err := wrapper.Wait(); err == ErrRestart {
+		err := wrapper.Wait()
+		if err == ErrRestart {
 			restart = true
+			continue
 		} else if err != nil {
 			log.Errorf("Failed to start: %s : %v", wrapper.name, err)
-			if firstError == nil {
-				firstError = err
-			}
 		}
+		results[wrapper.name] = err
 	}
 
 	if restart {
@@ -394,7 +450,7 @@ func (p *Project) traverse(start bool, selected map[string]bool, wrappers map[st
 		}
 		return p.traverse(false, selected, wrappers, action, cycleAction)
 	}
-	return firstError
+	return NewActionError(results)
 }
 
 // AddListener adds the specified listener to the project.
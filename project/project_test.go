@@ -2,6 +2,9 @@ package project
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
 	"reflect"
 	"strings"
 	"testing"
@@ -146,3 +149,67 @@ func TestEnvironmentResolve(t *testing.T) {
 		t.Fatal("Invalid environment", service.Config().Environment.Slice())
 	}
 }
+
+type testFileLookup struct{}
+
+func (l *testFileLookup) Lookup(file, relativeTo string) ([]byte, string, error) {
+	resolved := path.Join(path.Dir(relativeTo), file)
+	bytes, err := ioutil.ReadFile(resolved)
+	return bytes, resolved, err
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "project-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	composeFile := path.Join(dir, "docker-compose.yml")
+	write := func(file, content string) {
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(composeFile, "web:\n  image: nginx\n  env_file: web.env\n")
+	write(path.Join(dir, "web.env"), "FOO=bar\n")
+
+	p := NewProject(&Context{
+		ComposeFile:  composeFile,
+		ProjectName:  "reload",
+		ConfigLookup: &testFileLookup{},
+	})
+	if err := p.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{composeFile, path.Join(dir, "web.env")}, p.Files) {
+		t.Fatalf("Unexpected project files: %v", p.Files)
+	}
+
+	reloaded := false
+	p.ReloadCallback = func() error {
+		reloaded = true
+		return nil
+	}
+
+	write(composeFile, "web:\n  image: nginx:1.9\nredis:\n  image: redis\n")
+	if err := p.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reloaded || len(p.Configs) != 2 || p.Configs["web"].Image != "nginx:1.9" {
+		t.Fatalf("Project not reloaded: %v", p.Configs)
+	}
+	if !reflect.DeepEqual([]string{composeFile}, p.Files) {
+		t.Fatalf("Unexpected project files: %v", p.Files)
+	}
+
+	write(composeFile, "web: [")
+	if err := p.Reload(); err == nil {
+		t.Fatal("Invalid compose file reloaded")
+	}
+	if len(p.Configs) != 2 {
+		t.Fatalf("Configuration lost on failed reload: %v", p.Configs)
+	}
+}
@@ -59,6 +59,11 @@ const (
 	EventProjectPauseDone     = EventType(iota)
 	EventProjectUnpauseStart  = EventType(iota)
 	EventProjectUnpauseDone   = EventType(iota)
+
+	EventServiceRolloutProgress = EventType(iota)
+	EventServiceRollout         = EventType(iota)
+	EventServiceRollbackStart   = EventType(iota)
+	EventServiceRollback        = EventType(iota)
 )
 
 func (e EventType) String() string {
@@ -109,6 +114,14 @@ func (e EventType) String() string {
 		m = "Building"
 	case EventServiceBuild:
 		m = "Built"
+	case EventServiceRolloutProgress:
+		m = "Rolling out"
+	case EventServiceRollout:
+		m = "Rolled out"
+	case EventServiceRollbackStart:
+		m = "Rolling back"
+	case EventServiceRollback:
+		m = "Rolled back"
 
 	case EventProjectDownStart:
 		m = "Stopping project"
@@ -228,6 +241,7 @@ type Project struct {
 	Name           string
 	Configs        map[string]*ServiceConfig
 	File           string
+	Files          []string
 	ReloadCallback func() error
 	context        *Context
 	reload         []string
@@ -2,6 +2,7 @@ package project
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/flynn/go-shlex"
@@ -135,7 +136,7 @@ func (s *SliceorMap) UnmarshalYAML(tag string, value interface{}) error {
 		parts := map[string]string{}
 		for k, v := range value {
 			if sk, ok := k.(string); ok {
-				if sv, ok := v.(string); ok {
+				if sv, ok := scalarString(v); ok {
 					parts[sk] = sv
 				} else {
 					return fmt.Errorf("Cannot unmarshal '%v' of type %T into a string value", v, v)
@@ -193,6 +194,18 @@ func (s MaporEqualSlice) MarshalYAML() (tag string, value interface{}, err error
 	return "", s.parts, nil
 }
 
+// scalarString returns the string of a map value. Interpolation turns the
+// values looking like integers into integers, which are read back as strings.
+func scalarString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case int, int64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
 func toSepMapParts(value map[interface{}]interface{}, sep string) ([]string, error) {
 	if len(value) == 0 {
 		return nil, nil
@@ -200,7 +213,7 @@ func toSepMapParts(value map[interface{}]interface{}, sep string) ([]string, err
 	parts := make([]string, 0, len(value))
 	for k, v := range value {
 		if sk, ok := k.(string); ok {
-			if sv, ok := v.(string); ok {
+			if sv, ok := scalarString(v); ok {
 				parts = append(parts, sk+sep+sv)
 			} else {
 				return nil, fmt.Errorf("Cannot unmarshal '%v' of type %T into a string value", v, v)
@@ -209,6 +222,9 @@ func toSepMapParts(value map[interface{}]interface{}, sep string) ([]string, err
 			return nil, fmt.Errorf("Cannot unmarshal '%v' of type %T into a string value", k, k)
 		}
 	}
+	// Maps are iterated in random order, the parts are sorted so that the
+	// configuration, and its hash, do not change from one load to the next.
+	sort.Strings(parts)
 	return parts, nil
 }
 
@@ -193,6 +193,22 @@ func TestMaporsliceYaml(t *testing.T) {
 	assert.True(t, contains(s2.Foo.parts, "far=faz"))
 }
 
+func TestSliceOrMapInterpolatedInteger(t *testing.T) {
+	p := NewProject(&Context{
+		ComposeBytes:      []byte("web:\n  image: nginx\n  labels:\n    replicas: \"2\"\n  environment:\n    PORT: \"80\"\n"),
+		EnvironmentLookup: &TestEnvironmentLookup{},
+	})
+	assert.Nil(t, p.Parse())
+	assert.Equal(t, map[string]string{"replicas": "2"}, p.Configs["web"].Labels.MapParts())
+	assert.Equal(t, []string{"PORT=80"}, p.Configs["web"].Environment.Slice())
+}
+
+func TestMaporsliceYamlSorted(t *testing.T) {
+	s := StructMaporslice{}
+	assert.Nil(t, yaml.Unmarshal([]byte(`{foo: {d: "4", b: "2", a: "1", c: "3"}}`), &s))
+	assert.Equal(t, []string{"a=1", "b=2", "c=3", "d=4"}, s.Foo.Slice())
+}
+
 var sampleStructCommand = `command: bash`
 
 func TestUnmarshalCommand(t *testing.T) {
@@ -0,0 +1,581 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/pkg/nat"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+)
+
+func init() {
+	Register("ecs", &ECS{})
+}
+
+// ECSDefaultMemory is the hard memory limit, in MiB, of the containers of the
+// services without mem_limit, ECS requiring one.
+const ECSDefaultMemory = 128
+
+// The following types mirror the requests of the ECS API.
+
+// ECSTaskDefinition is the task definition of a group of linked services.
+type ECSTaskDefinition struct {
+	Family               string                    `json:"family"`
+	NetworkMode          string                    `json:"networkMode,omitempty"`
+	ContainerDefinitions []*ECSContainerDefinition `json:"containerDefinitions"`
+	Volumes              []ECSVolume               `json:"volumes,omitempty"`
+}
+
+// ECSContainerDefinition is the container of a service in a task definition.
+type ECSContainerDefinition struct {
+	Name                   string               `json:"name"`
+	Image                  string               `json:"image"`
+	CPU                    int64                `json:"cpu,omitempty"`
+	Memory                 int64                `json:"memory"`
+	Essential              bool                 `json:"essential"`
+	Links                  []string             `json:"links,omitempty"`
+	PortMappings           []ECSPortMapping     `json:"portMappings,omitempty"`
+	EntryPoint             []string             `json:"entryPoint,omitempty"`
+	Command                []string             `json:"command,omitempty"`
+	Environment            []ECSKeyValuePair    `json:"environment,omitempty"`
+	MountPoints            []ECSMountPoint      `json:"mountPoints,omitempty"`
+	VolumesFrom            []ECSVolumeFrom      `json:"volumesFrom,omitempty"`
+	Hostname               string               `json:"hostname,omitempty"`
+	User                   string               `json:"user,omitempty"`
+	WorkingDirectory       string               `json:"workingDirectory,omitempty"`
+	Privileged             bool                 `json:"privileged,omitempty"`
+	ReadonlyRootFilesystem bool                 `json:"readonlyRootFilesystem,omitempty"`
+	DNSServers             []string             `json:"dnsServers,omitempty"`
+	DNSSearchDomains       []string             `json:"dnsSearchDomains,omitempty"`
+	ExtraHosts             []ECSHostEntry       `json:"extraHosts,omitempty"`
+	DockerLabels           map[string]string    `json:"dockerLabels,omitempty"`
+	LogConfiguration       *ECSLogConfiguration `json:"logConfiguration,omitempty"`
+}
+
+// ECSPortMapping publishes a container port, on a host port chosen by ECS if 0.
+type ECSPortMapping struct {
+	ContainerPort int    `json:"containerPort"`
+	HostPort      int    `json:"hostPort"`
+	Protocol      string `json:"protocol"`
+}
+
+// ECSKeyValuePair is an environment variable.
+type ECSKeyValuePair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ECSMountPoint mounts a volume of the task in a container.
+type ECSMountPoint struct {
+	SourceVolume  string `json:"sourceVolume"`
+	ContainerPath string `json:"containerPath"`
+	ReadOnly      bool   `json:"readOnly,omitempty"`
+}
+
+// ECSVolumeFrom mounts the volumes of another container of the task.
+type ECSVolumeFrom struct {
+	SourceContainer string `json:"sourceContainer"`
+	ReadOnly        bool   `json:"readOnly,omitempty"`
+}
+
+// ECSHostEntry is an entry of /etc/hosts.
+type ECSHostEntry struct {
+	Hostname  string `json:"hostname"`
+	IPAddress string `json:"ipAddress"`
+}
+
+// ECSLogConfiguration selects the log driver of a container.
+type ECSLogConfiguration struct {
+	LogDriver string            `json:"logDriver"`
+	Options   map[string]string `json:"options,omitempty"`
+}
+
+// ECSVolume is a volume of a task, a host directory if the host is set.
+type ECSVolume struct {
+	Name string         `json:"name"`
+	Host *ECSVolumeHost `json:"host,omitempty"`
+}
+
+// ECSVolumeHost is the host directory of a volume.
+type ECSVolumeHost struct {
+	SourcePath string `json:"sourcePath"`
+}
+
+// ECSService runs the tasks of a task definition.
+type ECSService struct {
+	ServiceName    string `json:"serviceName"`
+	TaskDefinition string `json:"taskDefinition"`
+	DesiredCount   int    `json:"desiredCount"`
+}
+
+// ECSRule is the CloudWatch Events rule running the task of a scheduled job.
+// Its target, the cluster running the task, is added with PutTargets.
+type ECSRule struct {
+	Name               string `json:"Name"`
+	ScheduleExpression string `json:"ScheduleExpression"`
+	State              string `json:"State,omitempty"`
+}
+
+// ECS generates a task definition and a service per group of services
+// depending on each other, since links and shared volumes only work between
+// the containers of a task. The jobs of a group are non-essential containers,
+// which exit without stopping the task, and groups made of jobs only have no
+// service, their task being run with RunTask. Scheduled jobs get their own task
+// and a CloudWatch Events rule running it. The output is validated against
+// bundled schemas.
+type ECS struct{}
+
+// SupportedKeys implements Transformer.SupportedKeys.
+func (e *ECS) SupportedKeys() []string {
+	return []string{
+		"command",
+		"cpu_shares",
+		"dns",
+		"dns_search",
+		"entrypoint",
+		"env_file",
+		"environment",
+		"extra_hosts",
+		"hostname",
+		"image",
+		"labels",
+		"links",
+		"log_driver",
+		"log_opt",
+		"mem_limit",
+		"ports",
+		"privileged",
+		"read_only",
+		"user",
+		"volumes",
+		"volumes_from",
+		"working_dir",
+	}
+}
+
+// Transform implements Transformer.Transform.
+func (e *ECS) Transform(p *project.Project, options Options) ([]Artifact, error) {
+	if _, err := checkFormat(options.Format, "json"); err != nil {
+		return nil, err
+	}
+
+	artifacts := []Artifact{}
+	add := func(name, schema string, object interface{}) error {
+		data, err := json.MarshalIndent(object, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ValidateSchema([]byte(schema), data); err != nil {
+			return fmt.Errorf("Invalid %s: %v", name, err)
+		}
+		artifacts = append(artifacts, Artifact{Name: name, Data: append(data, '\n')})
+		return nil
+	}
+
+	for _, group := range ECSGroups(p) {
+		task, err := ConvertToECSTaskDefinition(p, group)
+		if err != nil {
+			return nil, err
+		}
+		if err := add(task.Family+"-task.json", ECSTaskDefinitionSchema, task); err != nil {
+			return nil, err
+		}
+
+		jobs := 0
+		for _, name := range group {
+			job, err := kubernetes.ParseJob(name, p.Configs[name])
+			if err != nil {
+				return nil, err
+			}
+			if job == nil {
+				continue
+			}
+			jobs++
+			warnJobLabels("ecs", name, p.Configs[name])
+
+			if job.Schedule != "" {
+				rule, err := ConvertToECSRule(name, task, job)
+				if err != nil {
+					return nil, err
+				}
+				if err := add(task.Family+"-rule.json", ECSRuleSchema, rule); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if jobs == len(group) {
+			continue
+		}
+
+		service, err := ConvertToECSService(p, group, task)
+		if err != nil {
+			return nil, err
+		}
+		if err := add(task.Family+"-service.json", ECSServiceSchema, service); err != nil {
+			return nil, err
+		}
+	}
+
+	return artifacts, nil
+}
+
+// ECSGroups returns the groups of services connected by their dependencies,
+// each one sorted, ordered by their first service. Scheduled jobs are alone in
+// their group, since their task runs on its own.
+func ECSGroups(p *project.Project) [][]string {
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(name string) string {
+		if parent[name] == name {
+			return name
+		}
+		parent[name] = find(parent[name])
+		return parent[name]
+	}
+
+	names := ServiceNames(p)
+	for _, name := range names {
+		parent[name] = name
+	}
+	for _, name := range names {
+		for _, dependency := range Dependencies(p, name) {
+			if ecsScheduled(p, name) || ecsScheduled(p, dependency) {
+				continue
+			}
+			a, b := find(name), find(dependency)
+			if a < b {
+				parent[b] = a
+			} else {
+				parent[a] = b
+			}
+		}
+	}
+
+	groups := [][]string{}
+	index := map[string]int{}
+	for _, name := range names {
+		root := find(name)
+		i, ok := index[root]
+		if !ok {
+			i = len(groups)
+			index[root] = i
+			groups = append(groups, []string{})
+		}
+		groups[i] = append(groups[i], name)
+	}
+
+	return groups
+}
+
+// ecsScheduled reports whether a service is a job with a schedule, the errors
+// of its labels being reported by the conversion of the service.
+func ecsScheduled(p *project.Project, name string) bool {
+	job, err := kubernetes.ParseJob(name, p.Configs[name])
+	return err == nil && job != nil && job.Schedule != ""
+}
+
+var ecsInvalidName = regexp.MustCompile("[^a-zA-Z0-9_-]+")
+
+// ecsName replaces the characters ECS does not allow in names.
+func ecsName(name string) string {
+	return strings.Trim(ecsInvalidName.ReplaceAllString(name, "-"), "-")
+}
+
+// ConvertToECSTaskDefinition converts a group of services to a task definition
+// named after the project and the first service of the group. The jobs of the
+// group are not essential, unless the group is made of jobs only.
+func ConvertToECSTaskDefinition(p *project.Project, group []string) (*ECSTaskDefinition, error) {
+	task := &ECSTaskDefinition{
+		Family: ecsName(p.Name + "-" + group[0]),
+	}
+
+	members := map[string]bool{}
+	jobs := map[string]bool{}
+	services := 0
+	for _, name := range group {
+		members[name] = true
+		job, err := kubernetes.ParseJob(name, p.Configs[name])
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			jobs[name] = true
+		} else {
+			services++
+		}
+	}
+
+	// Only the dependencies of scheduled jobs, or on them, cross groups.
+	for _, name := range group {
+		for _, dependency := range Dependencies(p, name) {
+			if members[dependency] {
+				continue
+			}
+			if ecsScheduled(p, name) {
+				return nil, project.NewValidationError(name, project.LabelField(kubernetes.ScheduleLabel), "scheduled jobs run in their own task, which cannot reach %s since ECS only links the containers of a task", dependency)
+			}
+			return nil, project.NewValidationError(name, "links", "%s is a scheduled job running in its own task, ECS only links the containers of a task", dependency)
+		}
+	}
+
+	// Volumes are shared by the containers of the task, by source.
+	volumes := map[string]string{}
+	volume := func(source string, host bool) string {
+		if name, ok := volumes[source]; ok {
+			return name
+		}
+		name := ecsName(source)
+		if name == "" {
+			name = "root"
+		}
+		for i := 2; ; i++ {
+			taken := false
+			for _, v := range task.Volumes {
+				taken = taken || v.Name == name
+			}
+			if !taken {
+				break
+			}
+			name = fmt.Sprintf("%s-%d", ecsName(source), i)
+		}
+		v := ECSVolume{Name: name}
+		if host {
+			v.Host = &ECSVolumeHost{SourcePath: source}
+		}
+		task.Volumes = append(task.Volumes, v)
+		volumes[source] = name
+		return name
+	}
+
+	for _, name := range group {
+		container, err := ecsContainer(p, name, volume)
+		if err != nil {
+			return nil, err
+		}
+		// The task would stop when a job exits.
+		if jobs[name] && services > 0 {
+			container.Essential = false
+		}
+		task.ContainerDefinitions = append(task.ContainerDefinitions, container)
+	}
+
+	return task, nil
+}
+
+func ecsContainer(p *project.Project, name string, volume func(source string, host bool) string) (*ECSContainerDefinition, error) {
+	c := p.Configs[name]
+
+	container := &ECSContainerDefinition{
+		Name:                   name,
+		Image:                  c.Image,
+		CPU:                    c.CPUShares,
+		Memory:                 ECSDefaultMemory,
+		Essential:              true,
+		EntryPoint:             c.Entrypoint.Slice(),
+		Command:                c.Command.Slice(),
+		Hostname:               c.Hostname,
+		User:                   c.User,
+		WorkingDirectory:       c.WorkingDir,
+		Privileged:             c.Privileged,
+		ReadonlyRootFilesystem: c.ReadOnly,
+		DNSServers:             c.DNS.Slice(),
+		DNSSearchDomains:       c.DNSSearch.Slice(),
+	}
+	if c.MemLimit > 0 {
+		container.Memory = c.MemLimit / (1024 * 1024)
+	}
+
+	for _, link := range c.Links.Slice() {
+		target, alias := project.NameAlias(link)
+		if _, ok := p.Configs[target]; !ok {
+			continue
+		}
+		if alias == target {
+			container.Links = append(container.Links, target)
+		} else {
+			container.Links = append(container.Links, target+":"+alias)
+		}
+	}
+
+	exposed, bindings, err := nat.ParsePortSpecs(c.Ports)
+	if err != nil {
+		return nil, project.NewValidationError(name, "ports", "%v", err)
+	}
+	ports := []nat.Port{}
+	for port := range exposed {
+		ports = append(ports, port)
+	}
+	sort.Sort(portsByNumber(ports))
+	for _, port := range ports {
+		mapping := ECSPortMapping{ContainerPort: port.Int(), Protocol: port.Proto()}
+		for _, binding := range bindings[port] {
+			if binding.HostPort != "" {
+				if mapping.HostPort, err = strconv.Atoi(binding.HostPort); err != nil {
+					return nil, project.NewValidationError(name, "ports", "invalid host port %s", binding.HostPort)
+				}
+				break
+			}
+		}
+		container.PortMappings = append(container.PortMappings, mapping)
+	}
+
+	env, err := Environment(name, c)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range env {
+		container.Environment = append(container.Environment, ECSKeyValuePair{Name: key, Value: value})
+	}
+	sort.Sort(envByName(container.Environment))
+
+	for _, v := range c.Volumes {
+		parts := strings.Split(v, ":")
+		mount := ECSMountPoint{}
+		switch {
+		case len(parts) == 1:
+			// Anonymous volumes are scratch volumes of the task.
+			mount.SourceVolume = volume(name+parts[0], false)
+			mount.ContainerPath = parts[0]
+		default:
+			mount.SourceVolume = volume(parts[0], strings.HasPrefix(parts[0], "/"))
+			mount.ContainerPath = parts[1]
+			mount.ReadOnly = len(parts) > 2 && parts[2] == "ro"
+		}
+		container.MountPoints = append(container.MountPoints, mount)
+	}
+
+	for _, from := range c.VolumesFrom {
+		parts := strings.SplitN(from, ":", 2)
+		container.VolumesFrom = append(container.VolumesFrom, ECSVolumeFrom{
+			SourceContainer: parts[0],
+			ReadOnly:        len(parts) == 2 && parts[1] == "ro",
+		})
+	}
+
+	for i, host := range c.ExtraHosts {
+		parts := strings.SplitN(host, ":", 2)
+		if len(parts) != 2 {
+			return nil, project.NewValidationError(name, fmt.Sprintf("extra_hosts[%d]", i), "expected HOST:IP, got %s", host)
+		}
+		container.ExtraHosts = append(container.ExtraHosts, ECSHostEntry{Hostname: parts[0], IPAddress: parts[1]})
+	}
+
+	if labels := Labels(c); len(labels) > 0 {
+		container.DockerLabels = labels
+	}
+
+	if c.LogDriver != "" {
+		container.LogConfiguration = &ECSLogConfiguration{
+			LogDriver: c.LogDriver,
+			Options:   c.LogOpt,
+		}
+	}
+
+	return container, nil
+}
+
+// ConvertToECSService generates the service running the tasks of a group,
+// with the highest minimum scale of its services.
+func ConvertToECSService(p *project.Project, group []string, task *ECSTaskDefinition) (*ECSService, error) {
+	count := 1
+	for i, name := range group {
+		autoscale, err := kubernetes.ParseAutoscale(name, p.Configs[name])
+		if err != nil {
+			return nil, err
+		}
+		if autoscale == nil {
+			autoscale = &kubernetes.Autoscale{Min: 1}
+		}
+		if i == 0 || autoscale.Min > count {
+			count = autoscale.Min
+		}
+	}
+
+	return &ECSService{
+		ServiceName:    task.Family,
+		TaskDefinition: task.Family,
+		DesiredCount:   count,
+	}, nil
+}
+
+// ConvertToECSRule generates the rule running the task of a scheduled job.
+func ConvertToECSRule(name string, task *ECSTaskDefinition, job *kubernetes.JobConfig) (*ECSRule, error) {
+	expression, err := ecsScheduleExpression(job.Schedule)
+	if err != nil {
+		return nil, project.NewValidationError(name, project.LabelField(kubernetes.ScheduleLabel), "%v", err)
+	}
+
+	return &ECSRule{
+		Name:               task.Family,
+		ScheduleExpression: expression,
+		State:              "ENABLED",
+	}, nil
+}
+
+// ecsScheduleExpression converts a cron schedule to a CloudWatch Events one,
+// like cron(0 3 * * ? *). CloudWatch numbers the days of week from 1, Sunday,
+// and one of the day of month and day of week must be ?, so they cannot both
+// be set.
+func ecsScheduleExpression(schedule string) (string, error) {
+	fields := cronFields(schedule)
+	if cronRestricted(fields[2]) && cronRestricted(fields[4]) {
+		return "", fmt.Errorf("schedules setting both the day of month and the day of week are not supported by CloudWatch Events")
+	}
+
+	number := func(value string) (string, error) {
+		n, err := cronNumber(value, nil, 0)
+		return strconv.Itoa(n), err
+	}
+	day := func(value string) (string, error) {
+		n, err := cronNumber(value, cronDays, 0)
+		if err != nil || n < 0 || n > 7 {
+			return "", fmt.Errorf("invalid day of week %s", value)
+		}
+		return strconv.Itoa(n%7 + 1), nil
+	}
+
+	minute, err := convertCronField(fields[0], "0", "-", number)
+	if err != nil {
+		return "", err
+	}
+	hour, err := convertCronField(fields[1], "0", "-", number)
+	if err != nil {
+		return "", err
+	}
+	dom, err := convertCronField(fields[2], "1", "-", number)
+	if err != nil {
+		return "", err
+	}
+	month, err := convertCronField(fields[3], "1", "-", func(value string) (string, error) {
+		n, err := cronNumber(value, cronMonths, 1)
+		return strconv.Itoa(n), err
+	})
+	if err != nil {
+		return "", err
+	}
+	dow, err := convertCronField(fields[4], "", "-", day)
+	if err != nil {
+		return "", err
+	}
+
+	if cronRestricted(dow) {
+		dom = "?"
+	} else {
+		dow = "?"
+		if dom == "?" {
+			dom = "*"
+		}
+	}
+	return fmt.Sprintf("cron(%s %s %s %s %s *)", minute, hour, dom, month, dow), nil
+}
+
+type envByName []ECSKeyValuePair
+
+func (e envByName) Len() int           { return len(e) }
+func (e envByName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e envByName) Less(i, j int) bool { return e[i].Name < e[j].Name }
@@ -0,0 +1,171 @@
+package transformer
+
+// The schemas of the ECS RegisterTaskDefinition and CreateService requests, and
+// of the CloudWatch Events PutRule request scheduling tasks, restricted to the
+// fields kompose generates, with the constraints documented in the API
+// references.
+
+// ECSTaskDefinitionSchema is the JSON schema of the generated task definitions.
+const ECSTaskDefinitionSchema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "title": "ECS task definition",
+  "type": "object",
+  "required": ["family", "containerDefinitions"],
+  "additionalProperties": false,
+  "properties": {
+    "family": {"type": "string", "pattern": "^[a-zA-Z0-9_-]{1,255}$"},
+    "networkMode": {"type": "string", "enum": ["bridge", "host", "none"]},
+    "containerDefinitions": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "type": "object",
+        "required": ["name", "image", "memory"],
+        "additionalProperties": false,
+        "properties": {
+          "name": {"type": "string", "pattern": "^[a-zA-Z0-9_-]{1,255}$"},
+          "image": {"type": "string", "minLength": 1, "maxLength": 255},
+          "cpu": {"type": "integer", "minimum": 0, "maximum": 10240},
+          "memory": {"type": "integer", "minimum": 4},
+          "essential": {"type": "boolean"},
+          "links": {"type": "array", "items": {"type": "string", "pattern": "^[a-zA-Z0-9_-]+(:[a-zA-Z0-9_.-]+)?$"}},
+          "portMappings": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["containerPort"],
+              "additionalProperties": false,
+              "properties": {
+                "containerPort": {"type": "integer", "minimum": 1, "maximum": 65535},
+                "hostPort": {"type": "integer", "minimum": 0, "maximum": 65535},
+                "protocol": {"type": "string", "enum": ["tcp", "udp"]}
+              }
+            }
+          },
+          "entryPoint": {"type": "array", "items": {"type": "string"}},
+          "command": {"type": "array", "items": {"type": "string"}},
+          "environment": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["name", "value"],
+              "additionalProperties": false,
+              "properties": {
+                "name": {"type": "string", "minLength": 1},
+                "value": {"type": "string"}
+              }
+            }
+          },
+          "mountPoints": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["sourceVolume", "containerPath"],
+              "additionalProperties": false,
+              "properties": {
+                "sourceVolume": {"type": "string", "minLength": 1},
+                "containerPath": {"type": "string", "pattern": "^/"},
+                "readOnly": {"type": "boolean"}
+              }
+            }
+          },
+          "volumesFrom": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["sourceContainer"],
+              "additionalProperties": false,
+              "properties": {
+                "sourceContainer": {"type": "string", "minLength": 1},
+                "readOnly": {"type": "boolean"}
+              }
+            }
+          },
+          "hostname": {"type": "string"},
+          "user": {"type": "string"},
+          "workingDirectory": {"type": "string"},
+          "privileged": {"type": "boolean"},
+          "readonlyRootFilesystem": {"type": "boolean"},
+          "dnsServers": {"type": "array", "items": {"type": "string"}},
+          "dnsSearchDomains": {"type": "array", "items": {"type": "string"}},
+          "extraHosts": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["hostname", "ipAddress"],
+              "additionalProperties": false,
+              "properties": {
+                "hostname": {"type": "string", "minLength": 1},
+                "ipAddress": {"type": "string", "minLength": 1}
+              }
+            }
+          },
+          "dockerLabels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "logConfiguration": {
+            "type": "object",
+            "required": ["logDriver"],
+            "additionalProperties": false,
+            "properties": {
+              "logDriver": {"type": "string", "enum": ["json-file", "syslog", "journald", "gelf", "fluentd", "awslogs", "splunk"]},
+              "options": {"type": "object", "additionalProperties": {"type": "string"}}
+            }
+          }
+        }
+      }
+    },
+    "volumes": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["name"],
+        "additionalProperties": false,
+        "properties": {
+          "name": {"type": "string", "pattern": "^[a-zA-Z0-9_-]{1,255}$"},
+          "host": {
+            "type": "object",
+            "additionalProperties": false,
+            "properties": {
+              "sourcePath": {"type": "string", "pattern": "^/"}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// ECSServiceSchema is the JSON schema of the generated service definitions.
+const ECSServiceSchema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "title": "ECS service",
+  "type": "object",
+  "required": ["serviceName", "taskDefinition", "desiredCount"],
+  "additionalProperties": false,
+  "properties": {
+    "serviceName": {"type": "string", "pattern": "^[a-zA-Z0-9_-]{1,255}$"},
+    "taskDefinition": {"type": "string", "minLength": 1},
+    "desiredCount": {"type": "integer", "minimum": 0},
+    "deploymentConfiguration": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "maximumPercent": {"type": "integer", "minimum": 100},
+        "minimumHealthyPercent": {"type": "integer", "minimum": 0, "maximum": 100}
+      }
+    }
+  }
+}`
+
+// ECSRuleSchema is the JSON schema of the generated schedule rules.
+const ECSRuleSchema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "title": "CloudWatch Events rule",
+  "type": "object",
+  "required": ["Name", "ScheduleExpression"],
+  "additionalProperties": false,
+  "properties": {
+    "Name": {"type": "string", "pattern": "^[a-zA-Z0-9_.-]{1,64}$"},
+    "ScheduleExpression": {"type": "string", "pattern": "^cron\\(\\S+ \\S+ \\S+ \\S+ \\S+ \\S+\\)$"},
+    "State": {"type": "string", "enum": ["ENABLED", "DISABLED"]}
+  }
+}`
@@ -0,0 +1,146 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECSGroups(t *testing.T) {
+	p := newTestProject()
+	p.Configs["worker"] = &project.ServiceConfig{Image: "worker", VolumesFrom: []string{"data"}}
+	p.Configs["data"] = &project.ServiceConfig{Image: "busybox"}
+	p.Configs["admin"] = &project.ServiceConfig{Image: "admin"}
+
+	assert.Equal(t, [][]string{{"admin"}, {"data", "worker"}, {"redis", "web"}}, ECSGroups(p))
+}
+
+func TestConvertToECSTaskDefinition(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis:cache"})
+	p.Configs["web"].Environment = project.NewMaporEqualSlice([]string{"B=2", "A=1"})
+	p.Configs["web"].MemLimit = 64 * 1024 * 1024
+	p.Configs["web"].CPUShares = 256
+	p.Configs["web"].Volumes = []string{"/srv/www:/usr/share/nginx/html:ro", "/var/cache/nginx"}
+	p.Configs["web"].LogDriver = "syslog"
+	p.Configs["web"].LogOpt = map[string]string{"tag": "web"}
+	p.Configs["redis"].Volumes = []string{"/srv/www:/data"}
+
+	task, err := ConvertToECSTaskDefinition(p, []string{"redis", "web"})
+	assert.Nil(t, err)
+	assert.Equal(t, "demo-redis", task.Family)
+	assert.Equal(t, []ECSVolume{
+		{Name: "srv-www", Host: &ECSVolumeHost{SourcePath: "/srv/www"}},
+		{Name: "web-var-cache-nginx"},
+	}, task.Volumes)
+
+	redis, web := task.ContainerDefinitions[0], task.ContainerDefinitions[1]
+	assert.Equal(t, int64(ECSDefaultMemory), redis.Memory)
+	assert.Equal(t, []ECSMountPoint{{SourceVolume: "srv-www", ContainerPath: "/data"}}, redis.MountPoints)
+
+	assert.Equal(t, []string{"redis:cache"}, web.Links)
+	assert.Equal(t, int64(64), web.Memory)
+	assert.Equal(t, int64(256), web.CPU)
+	assert.Equal(t, []ECSPortMapping{{ContainerPort: 80, HostPort: 80, Protocol: "tcp"}}, web.PortMappings)
+	assert.Equal(t, []ECSKeyValuePair{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}}, web.Environment)
+	assert.Equal(t, []ECSMountPoint{
+		{SourceVolume: "srv-www", ContainerPath: "/usr/share/nginx/html", ReadOnly: true},
+		{SourceVolume: "web-var-cache-nginx", ContainerPath: "/var/cache/nginx"},
+	}, web.MountPoints)
+	assert.Equal(t, &ECSLogConfiguration{LogDriver: "syslog", Options: map[string]string{"tag": "web"}}, web.LogConfiguration)
+}
+
+func TestECSTransform(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.autoscale.max": "5", "kompose.autoscale.min": "3"})
+
+	artifacts, err := Convert(p, "ecs", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo-redis-service.json", "demo-redis-task.json"}, names(artifacts))
+	assert.Contains(t, string(artifacts[0].Data), `"desiredCount": 3`)
+
+	assert.Nil(t, ValidateSchema([]byte(ECSServiceSchema), artifacts[0].Data))
+	assert.Nil(t, ValidateSchema([]byte(ECSTaskDefinitionSchema), artifacts[1].Data))
+
+	p.Configs["web"].LogDriver = "none"
+	_, err = Convert(p, "ecs", Options{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "containerDefinitions[1].logConfiguration.logDriver")
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "pattern": "^[a-z]+$"},
+			"count": {"type": "integer", "minimum": 1},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}}
+		}
+	}`)
+
+	assert.Nil(t, ValidateSchema(schema, []byte(`{"name": "web", "count": 2, "tags": ["a"]}`)))
+
+	for document, message := range map[string]string{
+		`{}`:                             "name is required",
+		`{"name": "Web"}`:                "name must match",
+		`{"name": "web", "count": 1.5}`:  "count must be of type integer",
+		`{"name": "web", "count": 0}`:    "count must be at least 1",
+		`{"name": "web", "tags": ["c"]}`: "tags[0] must be one of a, b",
+		`{"name": "web", "other": true}`: "other is not allowed",
+		`[]`:                             "document must be of type object",
+	} {
+		err := ValidateSchema(schema, []byte(document))
+		if assert.NotNil(t, err, document) {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+}
+
+func TestECSJobs(t *testing.T) {
+	p := newTestProject()
+	p.Configs["migrate"] = &project.ServiceConfig{
+		Image:  "nginx",
+		Links:  project.NewMaporColonSlice([]string{"redis"}),
+		Labels: project.NewSliceorMap(map[string]string{"kompose.job": "true"}),
+	}
+	p.Configs["report"] = &project.ServiceConfig{
+		Image:  "nginx",
+		Labels: project.NewSliceorMap(map[string]string{"kompose.schedule": "0 3 * * 1-5"}),
+	}
+
+	artifacts, err := Convert(p, "ecs", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo-migrate-service.json", "demo-migrate-task.json", "demo-report-rule.json", "demo-report-task.json"}, names(artifacts))
+	assert.Contains(t, string(artifacts[2].Data), `"ScheduleExpression": "cron(0 3 ? * 2-6 *)"`)
+	assert.Contains(t, string(artifacts[3].Data), `"essential": true`)
+
+	task, err := ConvertToECSTaskDefinition(p, []string{"migrate", "redis", "web"})
+	assert.Nil(t, err)
+	assert.False(t, task.ContainerDefinitions[0].Essential)
+	assert.True(t, task.ContainerDefinitions[1].Essential)
+
+	p.Configs["report"].Links = project.NewMaporColonSlice([]string{"redis"})
+	_, err = Convert(p, "ecs", Options{})
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "report.labels[kompose.schedule]", err.(*project.ValidationError).Path())
+	}
+}
+
+func TestECSScheduleExpression(t *testing.T) {
+	for schedule, expected := range map[string]string{
+		"0 3 * * *":     "cron(0 3 * * ? *)",
+		"*/10 * 1 * ?":  "cron(0/10 * 1 * ? *)",
+		"0 0 * JUN SUN": "cron(0 0 ? 6 1 *)",
+		"@monthly":      "cron(0 0 1 * ? *)",
+	} {
+		expression, err := ecsScheduleExpression(schedule)
+		assert.Nil(t, err, schedule)
+		assert.Equal(t, expected, expression, schedule)
+	}
+
+	_, err := ecsScheduleExpression("0 0 1 * MON")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,270 @@
+package transformer
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/lookup"
+	"github.com/docker/libcompose/project"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/assert"
+)
+
+// The golden corpus holds a directory per case in testdata, with its compose
+// file, an optional options.json holding the options of some targets and the
+// expected artifacts of each target in a sub-directory named after the target.
+// A target rejecting the case has its expected error in <target>.error instead.
+// Run the tests with -update to regenerate the artifacts after an intended
+// change, and review their diff.
+var update = flag.Bool("update", false, "regenerate the golden files of testdata")
+
+const goldenComposeFile = "docker-compose.yml"
+
+// goldenEnv resolves the variables of the compose files of the corpus, so that
+// their output does not depend on the environment of the test.
+type goldenEnv map[string]string
+
+func (e goldenEnv) Lookup(key, serviceName string, config *project.ServiceConfig) []string {
+	if value, ok := e[key]; ok {
+		return []string{key + "=" + value}
+	}
+	return []string{}
+}
+
+var goldenEnvironment = goldenEnv{
+	"TAG":      "1.9",
+	"DB_PASS":  "secret",
+	"WEB_PORT": "8080",
+}
+
+func goldenCases(t *testing.T) []string {
+	files, err := filepath.Glob(filepath.Join("testdata", "*", goldenComposeFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("No compose files found in testdata")
+	}
+
+	cases := []string{}
+	for _, file := range files {
+		cases = append(cases, filepath.Base(filepath.Dir(file)))
+	}
+	sort.Strings(cases)
+	return cases
+}
+
+func loadGoldenProject(t *testing.T, name string) *project.Project {
+	p := project.NewProject(&project.Context{
+		ComposeFile:       filepath.Join("testdata", name, goldenComposeFile),
+		ProjectName:       name,
+		ConfigLookup:      &lookup.FileConfigLookup{},
+		EnvironmentLookup: goldenEnvironment,
+	})
+	if err := p.Parse(); err != nil {
+		t.Fatalf("Failed to load case %s: %v", name, err)
+	}
+	return p
+}
+
+// loadGoldenOptions returns the options of a case by target, the targets
+// missing from its options.json using the default ones.
+func loadGoldenOptions(t *testing.T, name string) map[string]Options {
+	options := map[string]Options{}
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name, "options.json"))
+	if os.IsNotExist(err) {
+		return options
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &options); err != nil {
+		t.Fatalf("Invalid options of case %s: %v", name, err)
+	}
+	return options
+}
+
+// readGolden returns the content of the files of a directory by their path
+// relative to it.
+func readGolden(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestGolden(t *testing.T) {
+	// Warnings about unsupported keys are expected for most targets.
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(level)
+
+	for _, name := range goldenCases(t) {
+		options := loadGoldenOptions(t, name)
+
+		for _, target := range Targets() {
+			// Each conversion gets a fresh project, since transformers must
+			// not depend on the ones run before.
+			artifacts, err := Convert(loadGoldenProject(t, name), target, options[target])
+			dir := filepath.Join("testdata", name, target)
+			errorFile := dir + ".error"
+
+			if *update {
+				for _, path := range []string{dir, errorFile} {
+					if err := os.RemoveAll(path); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err != nil {
+					err = ioutil.WriteFile(errorFile, []byte(err.Error()+"\n"), 0644)
+				} else {
+					err = Write(dir, artifacts)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+
+			expectedError, readErr := ioutil.ReadFile(errorFile)
+			if err != nil {
+				if readErr != nil {
+					t.Errorf("Failed to convert case %s to %s: %v", name, target, err)
+				} else if strings.TrimSpace(string(expectedError)) != err.Error() {
+					t.Errorf("Case %s fails to convert to %s with %q, expected %q", name, target, err, strings.TrimSpace(string(expectedError)))
+				}
+				continue
+			} else if readErr == nil {
+				t.Errorf("Case %s converts to %s but %s expects an error, run the tests with -update", name, target, errorFile)
+				continue
+			}
+
+			golden := readGolden(t, dir)
+			for _, artifact := range artifacts {
+				expected, ok := golden[artifact.Name]
+				if !ok {
+					t.Errorf("%s/%s is generated but has no golden file, run the tests with -update", dir, artifact.Name)
+					continue
+				}
+				delete(golden, artifact.Name)
+
+				if expected == string(artifact.Data) {
+					continue
+				}
+				diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+					A:        difflib.SplitLines(expected),
+					B:        difflib.SplitLines(string(artifact.Data)),
+					FromFile: "golden/" + artifact.Name,
+					ToFile:   "generated/" + artifact.Name,
+					Context:  3,
+				})
+				t.Errorf("%s/%s differs from the generated one, run the tests with -update if intended:\n%s", dir, artifact.Name, diff)
+			}
+
+			for file := range golden {
+				t.Errorf("%s/%s is no longer generated, run the tests with -update", dir, file)
+			}
+		}
+	}
+}
+
+// TestGoldenDeterministic converts each case several times, the artifacts must
+// be identical for the golden files to be stable.
+func TestGoldenDeterministic(t *testing.T) {
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(level)
+
+	for _, name := range goldenCases(t) {
+		options := loadGoldenOptions(t, name)
+		for _, target := range Targets() {
+			first, err := Convert(loadGoldenProject(t, name), target, options[target])
+			if err != nil {
+				continue
+			}
+			for i := 0; i < 5; i++ {
+				next, err := Convert(loadGoldenProject(t, name), target, options[target])
+				assert.Nil(t, err)
+				assert.Equal(t, first, next, "case %s, target %s", name, target)
+			}
+		}
+	}
+}
+
+// TestGoldenCoverage checks every key of the compose reference is set by a
+// service of the corpus.
+func TestGoldenCoverage(t *testing.T) {
+	covered := map[string]bool{}
+	for _, name := range goldenCases(t) {
+		p := loadGoldenProject(t, name)
+		for _, config := range p.Configs {
+			for _, key := range UnsupportedKeys(config, nil) {
+				covered[key] = true
+			}
+		}
+	}
+
+	configType := reflect.TypeOf(project.ServiceConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		key := strings.Split(configType.Field(i).Tag.Get("yaml"), ",")[0]
+		// env_file is merged into environment when the file is loaded.
+		if key == "" || key == "env_file" {
+			continue
+		}
+		assert.True(t, covered[key], "No service of testdata sets %s", key)
+	}
+}
+
+// TestGoldenJobCommand checks the golden jobs run the command of their service,
+// rather than the default process of the image.
+func TestGoldenJobCommand(t *testing.T) {
+	for _, test := range []struct {
+		file string
+		path []string
+		args []interface{}
+	}{
+		{"migrate-job.json", []string{"spec", "template", "spec"}, []interface{}{"./manage.py", "migrate"}},
+		{"report-cronjob.json", []string{"spec", "jobTemplate", "spec", "template", "spec"}, []interface{}{"./manage.py", "report"}},
+	} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "jobs", "kubernetes", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		object := map[string]interface{}{}
+		if err := json.Unmarshal(data, &object); err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range test.path {
+			object = object[key].(map[string]interface{})
+		}
+
+		container := object["containers"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, test.args, container["args"], test.file)
+	}
+}
@@ -0,0 +1,179 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+)
+
+func init() {
+	Register("kubernetes", &Kubernetes{})
+}
+
+// Kubernetes generates a controller per service, a replication controller or
+// a deployment, along with its service, autoscaler and network policy. Services
+// labelled as jobs get a job or a cron job instead of a controller and service.
+type Kubernetes struct{}
+
+// SupportedKeys implements Transformer.SupportedKeys.
+func (k *Kubernetes) SupportedKeys() []string {
+	return []string{
+		"command",
+		"cpu_shares",
+		"entrypoint",
+		"env_file",
+		"environment",
+		"expose",
+		"image",
+		"labels",
+		"links",
+		"mem_limit",
+		"ports",
+		"privileged",
+		"restart",
+	}
+}
+
+// Transform implements Transformer.Transform.
+func (k *Kubernetes) Transform(p *project.Project, options Options) ([]Artifact, error) {
+	format, err := checkFormat(options.Format, "json", "yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := []Artifact{}
+
+	add := func(service, name, kind string, object interface{}) error {
+		// The objects of a service get the probe fields api.Probe lacks.
+		data, err := json.Marshal(object)
+		if service != "" {
+			data, err = kubernetes.EncodeObject(object, service, p.Configs[service])
+		}
+		if err != nil {
+			return err
+		}
+		data, ext, err := Reformat(data, format)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:    fmt.Sprintf("%s-%s.%s", name, kind, ext),
+			Service: service,
+			Data:    data,
+		})
+		return nil
+	}
+
+	pullSecretName := p.Name + "-registry"
+	pullSecretServices := map[string]bool{}
+	if options.PullSecrets {
+		config, err := cliconfig.Load(options.ConfigDir)
+		if err != nil {
+			return nil, err
+		}
+		secret, services, err := kubernetes.PullSecret(pullSecretName, p, config)
+		if err != nil {
+			return nil, err
+		}
+		if secret == nil {
+			logrus.Warnf("No credentials found in the docker configuration for the registries of project %s", p.Name)
+		} else {
+			if err := add("", pullSecretName, "secret", secret); err != nil {
+				return nil, err
+			}
+			// The secret holds credentials.
+			artifacts[len(artifacts)-1].Mode = 0600
+		}
+		for _, name := range services {
+			pullSecretServices[name] = true
+		}
+	}
+
+	for _, name := range ServiceNames(p) {
+		c := p.Configs[name]
+
+		objects, err := kubernetes.ConvertToAPI(p.Name, name, c)
+		if err != nil {
+			return nil, err
+		}
+
+		// Jobs replace the controller, and are not reached through a service.
+		var job *kubernetes.Job
+		var cronJob *kubernetes.CronJob
+
+		template := objects.ReplicationController.Spec.Template
+		kind := "ReplicationController"
+		if objects.Job != nil && objects.Job.Schedule != "" {
+			if cronJob, err = kubernetes.ConvertToCronJob(p.Name, name, c, objects.Job); err != nil {
+				return nil, err
+			}
+			template = &cronJob.Spec.JobTemplate.Spec.Template
+		} else if objects.Job != nil {
+			if job, err = kubernetes.ConvertToJob(p.Name, name, c, objects.Job); err != nil {
+				return nil, err
+			}
+			template = &job.Spec.Template
+		} else if options.Deployment {
+			template = objects.Deployment.Spec.Template
+			kind = "Deployment"
+		}
+
+		if options.WaitForDeps {
+			initContainers, err := kubernetes.DependencyInitContainers(p, name)
+			if err != nil {
+				return nil, err
+			}
+			if err := kubernetes.SetInitContainers(template, initContainers); err != nil {
+				return nil, err
+			}
+		}
+
+		if pullSecretServices[name] {
+			kubernetes.SetPullSecret(template, pullSecretName)
+		}
+
+		switch {
+		case cronJob != nil:
+			err = add(name, name, "cronjob", cronJob)
+		case job != nil:
+			err = add(name, name, "job", job)
+		case options.Deployment:
+			err = add(name, name, "deployment", objects.Deployment)
+		default:
+			err = add(name, name, "rc", objects.ReplicationController)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Services without ports cannot be reached, and are rejected by the API.
+		if objects.Job == nil && len(objects.Service.Spec.Ports) > 0 {
+			if err := add(name, name, "svc", objects.Service); err != nil {
+				return nil, err
+			}
+		}
+
+		if objects.Autoscale != nil {
+			hpa := kubernetes.ConvertToAutoscaler(p.Name, name, c, objects.Autoscale, kind)
+			if err := add(name, name, "hpa", hpa); err != nil {
+				return nil, err
+			}
+		}
+
+		if options.NetworkPolicies {
+			policy, err := kubernetes.ConvertToNetworkPolicy(p, name)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(name, name, "networkpolicy", policy); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return artifacts, nil
+}
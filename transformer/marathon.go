@@ -0,0 +1,454 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/nat"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+)
+
+func init() {
+	Register("marathon", &Marathon{})
+	kubernetes.RegisterDirective(ConstraintsLabel)
+}
+
+// ConstraintsLabel holds the Marathon constraints of a service, as a comma
+// separated list of field:OPERATOR[:value], like hostname:UNIQUE.
+const ConstraintsLabel = "kompose.constraints"
+
+var marathonOperators = map[string]bool{
+	"UNIQUE":   true,
+	"CLUSTER":  true,
+	"GROUP_BY": true,
+	"LIKE":     true,
+	"UNLIKE":   true,
+	"MAX_PER":  true,
+}
+
+// The following types mirror the ones of the Marathon REST API.
+
+// MarathonGroup is a Marathon application group holding the apps of a project.
+type MarathonGroup struct {
+	ID   string         `json:"id"`
+	Apps []*MarathonApp `json:"apps"`
+}
+
+// MarathonApp is the Marathon application of a service.
+type MarathonApp struct {
+	ID              string                    `json:"id"`
+	Instances       int                       `json:"instances"`
+	Cpus            float64                   `json:"cpus,omitempty"`
+	Mem             float64                   `json:"mem,omitempty"`
+	Args            []string                  `json:"args,omitempty"`
+	Env             map[string]string         `json:"env,omitempty"`
+	Labels          map[string]string         `json:"labels,omitempty"`
+	Constraints     [][]string                `json:"constraints,omitempty"`
+	Dependencies    []string                  `json:"dependencies,omitempty"`
+	Container       *MarathonContainer        `json:"container"`
+	HealthChecks    []*MarathonHealthCheck    `json:"healthChecks,omitempty"`
+	ReadinessChecks []*MarathonReadinessCheck `json:"readinessChecks,omitempty"`
+}
+
+// MarathonContainer runs the image of a service with docker.
+type MarathonContainer struct {
+	Type    string           `json:"type"`
+	Docker  *MarathonDocker  `json:"docker"`
+	Volumes []MarathonVolume `json:"volumes,omitempty"`
+}
+
+// MarathonDocker holds the docker options of an app, the parameters being
+// passed to docker run as is.
+type MarathonDocker struct {
+	Image        string                `json:"image"`
+	Network      string                `json:"network"`
+	PortMappings []MarathonPortMapping `json:"portMappings,omitempty"`
+	Privileged   bool                  `json:"privileged,omitempty"`
+	Parameters   []MarathonParameter   `json:"parameters,omitempty"`
+}
+
+// MarathonPortMapping maps a container port to a host port, allocated by
+// Marathon when 0.
+type MarathonPortMapping struct {
+	Name          string `json:"name"`
+	ContainerPort int    `json:"containerPort"`
+	HostPort      int    `json:"hostPort"`
+	Protocol      string `json:"protocol"`
+}
+
+// MarathonParameter is a docker run option.
+type MarathonParameter struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// MarathonVolume is a host directory mounted in the container.
+type MarathonVolume struct {
+	ContainerPath string `json:"containerPath"`
+	HostPath      string `json:"hostPath"`
+	Mode          string `json:"mode"`
+}
+
+// MarathonHealthCheck restarts the tasks failing it.
+type MarathonHealthCheck struct {
+	Protocol               string           `json:"protocol"`
+	Path                   string           `json:"path,omitempty"`
+	PortIndex              *int             `json:"portIndex,omitempty"`
+	Command                *MarathonCommand `json:"command,omitempty"`
+	GracePeriodSeconds     int64            `json:"gracePeriodSeconds,omitempty"`
+	IntervalSeconds        int64            `json:"intervalSeconds,omitempty"`
+	TimeoutSeconds         int64            `json:"timeoutSeconds,omitempty"`
+	MaxConsecutiveFailures int64            `json:"maxConsecutiveFailures,omitempty"`
+}
+
+// MarathonCommand is the shell command of a health check.
+type MarathonCommand struct {
+	Value string `json:"value"`
+}
+
+// MarathonReadinessCheck holds back deployments until the tasks pass it.
+type MarathonReadinessCheck struct {
+	Name            string `json:"name"`
+	Protocol        string `json:"protocol"`
+	Path            string `json:"path"`
+	PortName        string `json:"portName"`
+	IntervalSeconds int64  `json:"intervalSeconds,omitempty"`
+	TimeoutSeconds  int64  `json:"timeoutSeconds,omitempty"`
+}
+
+// Marathon generates a Marathon application group for the project, with an
+// app per service depending on the apps of the services it depends on.
+type Marathon struct{}
+
+// SupportedKeys implements Transformer.SupportedKeys.
+func (m *Marathon) SupportedKeys() []string {
+	return []string{
+		"cap_add",
+		"command",
+		"cpu_shares",
+		"dns",
+		"dns_search",
+		"env_file",
+		"environment",
+		"expose",
+		"hostname",
+		"image",
+		"ipc",
+		"labels",
+		"links",
+		"mem_limit",
+		"net",
+		"ports",
+		"privileged",
+		"user",
+		"volumes",
+		"volumes_from",
+		"working_dir",
+	}
+}
+
+// Transform implements Transformer.Transform.
+func (m *Marathon) Transform(p *project.Project, options Options) ([]Artifact, error) {
+	if _, err := checkFormat(options.Format, "json"); err != nil {
+		return nil, err
+	}
+
+	group, err := ConvertToMarathonGroup(p)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(group, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []Artifact{{Name: p.Name + ".marathon.json", Data: append(data, '\n')}}, nil
+}
+
+// ConvertToMarathonGroup converts the services of the project to the apps of
+// a group named after the project.
+func ConvertToMarathonGroup(p *project.Project) (*MarathonGroup, error) {
+	group := &MarathonGroup{
+		ID:   "/" + p.Name,
+		Apps: []*MarathonApp{},
+	}
+
+	for _, name := range ServiceNames(p) {
+		app, err := marathonApp(p, name)
+		if err != nil {
+			return nil, err
+		}
+		group.Apps = append(group.Apps, app)
+	}
+
+	return group, nil
+}
+
+func marathonApp(p *project.Project, name string) (*MarathonApp, error) {
+	c := p.Configs[name]
+
+	// Marathon restarts the tasks which exit, jobs run on Chronos or Metronome.
+	job, err := kubernetes.ParseJob(name, c)
+	if err != nil {
+		return nil, err
+	}
+	if job != nil {
+		label := kubernetes.JobLabel
+		if job.Schedule != "" {
+			label = kubernetes.ScheduleLabel
+		}
+		return nil, project.NewValidationError(name, project.LabelField(label), "jobs are not supported by the marathon target, which restarts the apps that exit")
+	}
+
+	app := &MarathonApp{
+		ID:        fmt.Sprintf("/%s/%s", p.Name, name),
+		Instances: 1,
+		Args:      c.Command.Slice(),
+		Container: &MarathonContainer{
+			Type: "DOCKER",
+			Docker: &MarathonDocker{
+				Image:      c.Image,
+				Network:    "BRIDGE",
+				Privileged: c.Privileged,
+			},
+		},
+	}
+
+	autoscale, err := kubernetes.ParseAutoscale(name, c)
+	if err != nil {
+		return nil, err
+	}
+	if autoscale != nil {
+		app.Instances = autoscale.Min
+	}
+
+	if c.CPUShares > 0 {
+		app.Cpus = float64(c.CPUShares) / 1024
+	}
+	if c.MemLimit > 0 {
+		app.Mem = float64(c.MemLimit) / (1024 * 1024)
+	}
+
+	env, err := Environment(name, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(env) > 0 {
+		app.Env = env
+	}
+
+	if labels := Labels(c); len(labels) > 0 {
+		app.Labels = labels
+	}
+
+	if app.Constraints, err = marathonConstraints(name, c); err != nil {
+		return nil, err
+	}
+
+	for _, dependency := range Dependencies(p, name) {
+		app.Dependencies = append(app.Dependencies, fmt.Sprintf("/%s/%s", p.Name, dependency))
+	}
+
+	if app.Container.Docker.PortMappings, err = marathonPortMappings(name, c); err != nil {
+		return nil, err
+	}
+
+	app.Container.Docker.Parameters = marathonParameters(c)
+
+	for _, volume := range c.Volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 {
+			logrus.Warnf("Service %s: volume %s has no host path, it is not supported by the marathon target and is ignored", name, volume)
+			continue
+		}
+		mode := "RW"
+		if len(parts) > 2 && parts[2] == "ro" {
+			mode = "RO"
+		}
+		app.Container.Volumes = append(app.Container.Volumes, MarathonVolume{
+			HostPath:      parts[0],
+			ContainerPath: parts[1],
+			Mode:          mode,
+		})
+	}
+
+	if err := marathonChecks(name, c, app); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// marathonPortMappings maps the published and exposed ports of a service,
+// sorted by container port. Exposed ports get a host port allocated by Marathon.
+func marathonPortMappings(name string, c *project.ServiceConfig) ([]MarathonPortMapping, error) {
+	exposed, bindings, err := nat.ParsePortSpecs(append(append([]string{}, c.Ports...), c.Expose...))
+	if err != nil {
+		return nil, project.NewValidationError(name, "ports", "%v", err)
+	}
+
+	ports := []nat.Port{}
+	for port := range exposed {
+		ports = append(ports, port)
+	}
+	sort.Sort(portsByNumber(ports))
+
+	mappings := []MarathonPortMapping{}
+	for _, port := range ports {
+		mapping := MarathonPortMapping{
+			Name:          fmt.Sprintf("%s%d", port.Proto(), port.Int()),
+			ContainerPort: port.Int(),
+			Protocol:      port.Proto(),
+		}
+		for _, binding := range bindings[port] {
+			if binding.HostPort != "" {
+				if mapping.HostPort, err = strconv.Atoi(binding.HostPort); err != nil {
+					return nil, project.NewValidationError(name, "ports", "invalid host port %s", binding.HostPort)
+				}
+				break
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+// marathonParameters returns the docker run options of the keys Marathon has
+// no field for.
+func marathonParameters(c *project.ServiceConfig) []MarathonParameter {
+	parameters := []MarathonParameter{}
+	add := func(key string, values ...string) {
+		for _, value := range values {
+			if value != "" {
+				parameters = append(parameters, MarathonParameter{Key: key, Value: value})
+			}
+		}
+	}
+
+	add("hostname", c.Hostname)
+	add("user", c.User)
+	add("workdir", c.WorkingDir)
+	add("dns", c.DNS.Slice()...)
+	add("dns-search", c.DNSSearch.Slice()...)
+	add("cap-add", c.CapAdd...)
+	add("net", c.Net)
+	add("ipc", c.Ipc)
+	add("volumes-from", c.VolumesFrom...)
+
+	return parameters
+}
+
+// marathonConstraints parses the constraints label of a service.
+func marathonConstraints(name string, c *project.ServiceConfig) ([][]string, error) {
+	value, ok := c.Labels.MapParts()[ConstraintsLabel]
+	if !ok {
+		return nil, nil
+	}
+
+	constraints := [][]string{}
+	for _, spec := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(spec), ":", 3)
+		if len(parts) < 2 || parts[0] == "" || !marathonOperators[parts[1]] {
+			return nil, project.NewValidationError(name, project.LabelField(ConstraintsLabel), "invalid constraint %s, expected field:OPERATOR[:value]", spec)
+		}
+		constraints = append(constraints, parts)
+	}
+
+	return constraints, nil
+}
+
+// marathonChecks converts the liveness probe of a service to a health check,
+// and its readiness probe to a readiness check, which Marathon only supports
+// over HTTP.
+func marathonChecks(name string, c *project.ServiceConfig, app *MarathonApp) error {
+	labels := c.Labels.MapParts()
+
+	portIndex := func(label string, port int) (int, error) {
+		for i, mapping := range app.Container.Docker.PortMappings {
+			if mapping.ContainerPort == port {
+				return i, nil
+			}
+		}
+		return 0, project.NewValidationError(name, project.LabelField(label), "port %d is not published nor exposed", port)
+	}
+
+	if spec, ok := labels[kubernetes.LivenessProbeLabel]; ok {
+		probe, err := kubernetes.ParseProbe(spec)
+		if err != nil {
+			return project.NewValidationError(name, project.LabelField(kubernetes.LivenessProbeLabel), "%v", err)
+		}
+		opts, err := kubernetes.ProbeOptions(name, labels, kubernetes.LivenessProbeLabel)
+		if err != nil {
+			return err
+		}
+		if _, ok := opts["success-threshold"]; ok {
+			logrus.Warnf("Service %s: %s.success-threshold is not supported by the marathon target and is ignored", name, kubernetes.LivenessProbeLabel)
+		}
+
+		check := &MarathonHealthCheck{
+			GracePeriodSeconds:     opts["delay"],
+			TimeoutSeconds:         opts["timeout"],
+			IntervalSeconds:        opts["period"],
+			MaxConsecutiveFailures: opts["threshold"],
+		}
+		switch {
+		case probe.HTTPGet != nil:
+			index, err := portIndex(kubernetes.LivenessProbeLabel, probe.HTTPGet.Port.IntVal)
+			if err != nil {
+				return err
+			}
+			check.Protocol, check.Path, check.PortIndex = "HTTP", probe.HTTPGet.Path, &index
+		case probe.TCPSocket != nil:
+			index, err := portIndex(kubernetes.LivenessProbeLabel, probe.TCPSocket.Port.IntVal)
+			if err != nil {
+				return err
+			}
+			check.Protocol, check.PortIndex = "TCP", &index
+		case probe.Exec != nil:
+			check.Protocol = "COMMAND"
+			// The shell runs the command as written in the label.
+			check.Command = &MarathonCommand{Value: strings.TrimPrefix(strings.TrimSpace(spec), "exec:")}
+		}
+		app.HealthChecks = append(app.HealthChecks, check)
+	}
+
+	if spec, ok := labels[kubernetes.ReadinessProbeLabel]; ok {
+		probe, err := kubernetes.ParseProbe(spec)
+		if err != nil {
+			return project.NewValidationError(name, project.LabelField(kubernetes.ReadinessProbeLabel), "%v", err)
+		}
+		opts, err := kubernetes.ProbeOptions(name, labels, kubernetes.ReadinessProbeLabel)
+		if err != nil {
+			return err
+		}
+		if _, ok := opts["success-threshold"]; ok {
+			logrus.Warnf("Service %s: %s.success-threshold is not supported by the marathon target and is ignored", name, kubernetes.ReadinessProbeLabel)
+		}
+
+		if probe.HTTPGet == nil {
+			logrus.Warnf("Service %s: Marathon only supports HTTP readiness checks, %s is ignored", name, spec)
+			return nil
+		}
+		index, err := portIndex(kubernetes.ReadinessProbeLabel, probe.HTTPGet.Port.IntVal)
+		if err != nil {
+			return err
+		}
+		app.ReadinessChecks = append(app.ReadinessChecks, &MarathonReadinessCheck{
+			Name:            "readiness",
+			Protocol:        "HTTP",
+			Path:            probe.HTTPGet.Path,
+			PortName:        app.Container.Docker.PortMappings[index].Name,
+			IntervalSeconds: opts["period"],
+			TimeoutSeconds:  opts["timeout"],
+		})
+	}
+
+	return nil
+}
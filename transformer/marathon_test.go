@@ -0,0 +1,93 @@
+package transformer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToMarathonGroup(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Command = project.NewCommand("nginx", "-g", "daemon off;")
+	p.Configs["web"].CPUShares = 512
+	p.Configs["web"].Volumes = []string{"/srv/www:/usr/share/nginx/html:ro", "/cache"}
+	p.Configs["web"].DNS = project.NewStringorslice("8.8.8.8")
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{
+		"com.example.tier":             "front",
+		ConstraintsLabel:               "hostname:UNIQUE, rack:LIKE:rack-[1-3]",
+		"kompose.probe.liveness":       "http:80/healthz",
+		"kompose.probe.readiness":      "http:80/ready",
+		"kompose.probe.liveness.delay": "15",
+	})
+	p.Configs["redis"].Expose = []string{"6379"}
+	p.Configs["redis"].Labels = project.NewSliceorMap(map[string]string{"kompose.probe.liveness": "exec:redis-cli ping"})
+
+	group, err := ConvertToMarathonGroup(p)
+	assert.Nil(t, err)
+	assert.Equal(t, "/demo", group.ID)
+
+	redis, web := group.Apps[0], group.Apps[1]
+	assert.Equal(t, "/demo/redis", redis.ID)
+	assert.Equal(t, []MarathonPortMapping{{Name: "tcp6379", ContainerPort: 6379, Protocol: "tcp"}}, redis.Container.Docker.PortMappings)
+	assert.Equal(t, "COMMAND", redis.HealthChecks[0].Protocol)
+	assert.Equal(t, "redis-cli ping", redis.HealthChecks[0].Command.Value)
+
+	assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, web.Args)
+	assert.Equal(t, 0.5, web.Cpus)
+	assert.Equal(t, []string{"/demo/redis"}, web.Dependencies)
+	assert.Equal(t, map[string]string{"com.example.tier": "front"}, web.Labels)
+	assert.Equal(t, [][]string{{"hostname", "UNIQUE"}, {"rack", "LIKE", "rack-[1-3]"}}, web.Constraints)
+	assert.Equal(t, []MarathonPortMapping{{Name: "tcp80", ContainerPort: 80, HostPort: 80, Protocol: "tcp"}}, web.Container.Docker.PortMappings)
+	assert.Equal(t, []MarathonVolume{{HostPath: "/srv/www", ContainerPath: "/usr/share/nginx/html", Mode: "RO"}}, web.Container.Volumes)
+	assert.Equal(t, []MarathonParameter{{Key: "dns", Value: "8.8.8.8"}}, web.Container.Docker.Parameters)
+	assert.Equal(t, "HTTP", web.HealthChecks[0].Protocol)
+	assert.Equal(t, "/healthz", web.HealthChecks[0].Path)
+	assert.Equal(t, 0, *web.HealthChecks[0].PortIndex)
+	assert.Equal(t, int64(15), web.HealthChecks[0].GracePeriodSeconds)
+	assert.Equal(t, "tcp80", web.ReadinessChecks[0].PortName)
+}
+
+func TestConvertToMarathonGroupInvalid(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{ConstraintsLabel: "hostname:SOMEWHERE"})
+	_, err := ConvertToMarathonGroup(p)
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "web.labels[kompose.constraints]", err.(*project.ValidationError).Path())
+	}
+
+	p = newTestProject()
+	p.Configs["redis"].Labels = project.NewSliceorMap(map[string]string{"kompose.probe.liveness": "tcp:6379"})
+	_, err = ConvertToMarathonGroup(p)
+	assert.NotNil(t, err)
+
+	p = newTestProject()
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.probe.liveness": "http:80", "kompose.probe.liveness.period": "often"})
+	_, err = ConvertToMarathonGroup(p)
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "web.labels[kompose.probe.liveness.period]", err.(*project.ValidationError).Path())
+	}
+}
+
+func TestMarathonTransform(t *testing.T) {
+	artifacts, err := Convert(newTestProject(), "marathon", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo.marathon.json"}, names(artifacts))
+
+	group := MarathonGroup{}
+	assert.Nil(t, json.Unmarshal(artifacts[0].Data, &group))
+	assert.Equal(t, 2, len(group.Apps))
+
+	_, err = Convert(newTestProject(), "marathon", Options{Format: "yaml"})
+	assert.NotNil(t, err)
+}
+
+func TestMarathonJobs(t *testing.T) {
+	p := newTestProject()
+	p.Configs["redis"].Labels = project.NewSliceorMap(map[string]string{"kompose.schedule": "0 3 * * *"})
+	_, err := Convert(p, "marathon", Options{})
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "redis.labels[kompose.schedule]", err.(*project.ValidationError).Path())
+	}
+}
@@ -0,0 +1,631 @@
+package transformer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/nat"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+)
+
+func init() {
+	Register("nomad", &Nomad{})
+}
+
+// Nomad defaults, used when the service sets no limit.
+const (
+	NomadDatacenter    = "dc1"
+	NomadDefaultCPU    = 100
+	NomadDefaultMemory = 256
+	NomadNetworkMBits  = 10
+)
+
+// The following types mirror the ones of the Nomad job API, Nomad is not
+// vendored.
+
+// NomadJob is a Nomad job with one task group per service.
+type NomadJob struct {
+	ID          string
+	Name        string
+	Type        string
+	Datacenters []string
+	Periodic    *NomadPeriodic `json:",omitempty"`
+	TaskGroups  []*NomadTaskGroup
+}
+
+// NomadPeriodic runs a batch job on a cron schedule.
+type NomadPeriodic struct {
+	Enabled         bool
+	SpecType        string
+	Spec            string
+	ProhibitOverlap bool
+}
+
+// NomadTaskGroup is the group of a service, running count instances of its task.
+type NomadTaskGroup struct {
+	Name          string
+	Count         int
+	RestartPolicy *NomadRestartPolicy `json:",omitempty"`
+	Tasks         []*NomadTask
+}
+
+// NomadRestartPolicy limits the restarts of the failed tasks of a group, the
+// durations being in nanoseconds.
+type NomadRestartPolicy struct {
+	Attempts int
+	Interval int64
+	Delay    int64
+	Mode     string
+}
+
+// NomadTask runs the container of a service with the docker driver.
+type NomadTask struct {
+	Name      string
+	Driver    string
+	Config    map[string]interface{}
+	Env       map[string]string `json:",omitempty"`
+	Services  []*NomadService   `json:",omitempty"`
+	Resources *NomadResources
+}
+
+// NomadService is the Consul registration of a task.
+type NomadService struct {
+	Name      string
+	Tags      []string `json:",omitempty"`
+	PortLabel string
+	Checks    []*NomadServiceCheck `json:",omitempty"`
+}
+
+// NomadServiceCheck is a Consul health check of a service.
+type NomadServiceCheck struct {
+	Name     string
+	Type     string
+	Interval int64
+	Timeout  int64
+}
+
+// NomadResources holds the resources reserved for a task, the CPU in MHz and
+// the memory in MB.
+type NomadResources struct {
+	CPU      int
+	MemoryMB int
+	Networks []*NomadNetwork `json:",omitempty"`
+}
+
+// NomadNetwork holds the ports allocated to a task.
+type NomadNetwork struct {
+	MBits         int
+	ReservedPorts []NomadPort `json:",omitempty"`
+	DynamicPorts  []NomadPort `json:",omitempty"`
+}
+
+// NomadPort is a port allocated to a task, Value is 0 for dynamic ports.
+type NomadPort struct {
+	Label string
+	Value int `json:",omitempty"`
+}
+
+// Nomad generates a Nomad service job for the project, and a batch job per
+// service labelled as a job, periodic if it has a schedule. Links become Consul
+// service registrations of the linked services, which the linking ones reach
+// through the Consul DNS interface.
+type Nomad struct{}
+
+// SupportedKeys implements Transformer.SupportedKeys.
+func (n *Nomad) SupportedKeys() []string {
+	return []string{
+		"cap_add",
+		"command",
+		"cpu_shares",
+		"dns",
+		"dns_search",
+		"env_file",
+		"environment",
+		"expose",
+		"hostname",
+		"image",
+		"labels",
+		"links",
+		"mem_limit",
+		"ports",
+		"privileged",
+		"volumes",
+	}
+}
+
+// Transform implements Transformer.Transform.
+func (n *Nomad) Transform(p *project.Project, options Options) ([]Artifact, error) {
+	format, err := checkFormat(options.Format, "hcl", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []*NomadJob{}
+	job, err := ConvertToNomadJob(p)
+	if err != nil {
+		return nil, err
+	}
+	// Projects made of jobs only have no service job.
+	if len(job.TaskGroups) > 0 {
+		jobs = append(jobs, job)
+	}
+
+	for _, name := range ServiceNames(p) {
+		config, err := kubernetes.ParseJob(name, p.Configs[name])
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			continue
+		}
+		job, err := ConvertToNomadBatchJob(p, name, config)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	artifacts := []Artifact{}
+	for _, job := range jobs {
+		var data []byte
+		name := job.ID + ".nomad"
+		if format == "json" {
+			if data, err = json.MarshalIndent(map[string]*NomadJob{"Job": job}, "", "  "); err != nil {
+				return nil, err
+			}
+			data = append(data, '\n')
+			name += ".json"
+		} else {
+			data = job.HCL()
+		}
+		artifacts = append(artifacts, Artifact{Name: name, Data: data})
+	}
+
+	return artifacts, nil
+}
+
+// ConvertToNomadJob converts the services of the project which are not jobs
+// to the task groups of a service job named after the project.
+func ConvertToNomadJob(p *project.Project) (*NomadJob, error) {
+	job := &NomadJob{
+		ID:          p.Name,
+		Name:        p.Name,
+		Type:        "service",
+		Datacenters: []string{NomadDatacenter},
+	}
+
+	registrations := nomadRegistrations(p)
+
+	for _, name := range ServiceNames(p) {
+		c := p.Configs[name]
+
+		config, err := kubernetes.ParseJob(name, c)
+		if err != nil {
+			return nil, err
+		}
+		if config != nil {
+			continue
+		}
+
+		task, err := nomadTask(name, c)
+		if err != nil {
+			return nil, err
+		}
+
+		if names, ok := registrations[name]; ok {
+			if task.Resources.Networks == nil {
+				logrus.Warnf("Service %s is linked but exposes no port, it is not registered in Consul", name)
+			} else {
+				label := nomadFirstPortLabel(task)
+				for _, registration := range names {
+					task.Services = append(task.Services, &NomadService{
+						Name:      registration,
+						Tags:      []string{p.Name},
+						PortLabel: label,
+						Checks: []*NomadServiceCheck{{
+							Name:     registration + " alive",
+							Type:     "tcp",
+							Interval: int64(10 * time.Second),
+							Timeout:  int64(2 * time.Second),
+						}},
+					})
+				}
+			}
+		}
+
+		count := 1
+		autoscale, err := kubernetes.ParseAutoscale(name, c)
+		if err != nil {
+			return nil, err
+		}
+		if autoscale != nil {
+			count = autoscale.Min
+		}
+
+		job.TaskGroups = append(job.TaskGroups, &NomadTaskGroup{
+			Name:  name,
+			Count: count,
+			Tasks: []*NomadTask{task},
+		})
+	}
+
+	return job, nil
+}
+
+// ConvertToNomadBatchJob converts a service labelled as a job to a batch job
+// named after the project and the service, running its task the number of
+// completions of the job. Jobs with a schedule are periodic.
+func ConvertToNomadBatchJob(p *project.Project, name string, config *kubernetes.JobConfig) (*NomadJob, error) {
+	c := p.Configs[name]
+	warnJobLabels("nomad", name, c, kubernetes.JobCompletionsLabel, kubernetes.JobBackoffLimitLabel)
+
+	if _, ok := nomadRegistrations(p)[name]; ok {
+		logrus.Warnf("Service %s is linked but is a job, it is not registered in Consul", name)
+	}
+
+	task, err := nomadTask(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &NomadTaskGroup{
+		Name:  name,
+		Count: 1,
+		Tasks: []*NomadTask{task},
+	}
+	if config.Completions != nil {
+		group.Count = *config.Completions
+	}
+	if config.BackoffLimit != nil {
+		// The interval and delay are the defaults of Nomad batch jobs.
+		group.RestartPolicy = &NomadRestartPolicy{
+			Attempts: *config.BackoffLimit,
+			Interval: int64(24 * time.Hour),
+			Delay:    int64(15 * time.Second),
+			Mode:     "fail",
+		}
+	}
+
+	id := p.Name + "-" + name
+	job := &NomadJob{
+		ID:          id,
+		Name:        id,
+		Type:        "batch",
+		Datacenters: []string{NomadDatacenter},
+		TaskGroups:  []*NomadTaskGroup{group},
+	}
+	if config.Schedule != "" {
+		job.Periodic = &NomadPeriodic{
+			Enabled:         true,
+			SpecType:        "cron",
+			Spec:            config.Schedule,
+			ProhibitOverlap: true,
+		}
+	}
+
+	return job, nil
+}
+
+// nomadRegistrations returns the names each linked service is registered
+// under in Consul, its name and the aliases of the links to it.
+func nomadRegistrations(p *project.Project) map[string][]string {
+	registrations := map[string][]string{}
+	for _, name := range ServiceNames(p) {
+		for _, link := range p.Configs[name].Links.Slice() {
+			linked, alias := project.NameAlias(link)
+			if _, ok := p.Configs[linked]; !ok {
+				continue
+			}
+			registrations[linked] = appendUnique(registrations[linked], linked)
+			registrations[linked] = appendUnique(registrations[linked], alias)
+		}
+	}
+	return registrations
+}
+
+func nomadTask(name string, c *project.ServiceConfig) (*NomadTask, error) {
+	config := map[string]interface{}{
+		"image": c.Image,
+	}
+
+	if command := c.Command.Slice(); len(command) > 0 {
+		config["command"] = command[0]
+		if len(command) > 1 {
+			config["args"] = command[1:]
+		}
+	}
+	if c.Privileged {
+		config["privileged"] = true
+	}
+	if c.Hostname != "" {
+		config["hostname"] = c.Hostname
+	}
+	if dns := c.DNS.Slice(); len(dns) > 0 {
+		config["dns_servers"] = dns
+	}
+	if search := c.DNSSearch.Slice(); len(search) > 0 {
+		config["dns_search_domains"] = search
+	}
+	if len(c.CapAdd) > 0 {
+		config["cap_add"] = c.CapAdd
+	}
+	if len(c.Volumes) > 0 {
+		config["volumes"] = c.Volumes
+	}
+	if labels := Labels(c); len(labels) > 0 {
+		config["labels"] = []map[string]string{labels}
+	}
+
+	env, err := Environment(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := &NomadResources{
+		CPU:      NomadDefaultCPU,
+		MemoryMB: NomadDefaultMemory,
+	}
+	if c.CPUShares > 0 {
+		// Like the CPU requests of kubernetes, 1024 shares are one CPU, taken as 1GHz.
+		resources.CPU = int(c.CPUShares * 1000 / 1024)
+	}
+	if c.MemLimit > 0 {
+		resources.MemoryMB = int(c.MemLimit / (1024 * 1024))
+	}
+
+	network, portMap, err := nomadNetwork(name, c)
+	if err != nil {
+		return nil, err
+	}
+	if network != nil {
+		resources.Networks = []*NomadNetwork{network}
+		config["port_map"] = []map[string]int{portMap}
+	}
+
+	task := &NomadTask{
+		Name:      name,
+		Driver:    "docker",
+		Config:    config,
+		Resources: resources,
+	}
+	if len(env) > 0 {
+		task.Env = env
+	}
+
+	return task, nil
+}
+
+// nomadNetwork allocates a port per published or exposed container port,
+// reserved when the host port is set and dynamic otherwise, along with the
+// mapping of the port labels to the container ports.
+func nomadNetwork(name string, c *project.ServiceConfig) (*NomadNetwork, map[string]int, error) {
+	exposed, bindings, err := nat.ParsePortSpecs(append(append([]string{}, c.Ports...), c.Expose...))
+	if err != nil {
+		return nil, nil, project.NewValidationError(name, "ports", "%v", err)
+	}
+	if len(exposed) == 0 {
+		return nil, nil, nil
+	}
+
+	ports := []nat.Port{}
+	for port := range exposed {
+		ports = append(ports, port)
+	}
+	sort.Sort(portsByNumber(ports))
+
+	network := &NomadNetwork{MBits: NomadNetworkMBits}
+	portMap := map[string]int{}
+	for _, port := range ports {
+		label := fmt.Sprintf("%s%d", port.Proto(), port.Int())
+		portMap[label] = port.Int()
+
+		hostPort := 0
+		for _, binding := range bindings[port] {
+			if binding.HostPort != "" {
+				if hostPort, err = strconv.Atoi(binding.HostPort); err != nil {
+					return nil, nil, project.NewValidationError(name, "ports", "invalid host port %s", binding.HostPort)
+				}
+				break
+			}
+		}
+
+		if hostPort > 0 {
+			network.ReservedPorts = append(network.ReservedPorts, NomadPort{Label: label, Value: hostPort})
+		} else {
+			network.DynamicPorts = append(network.DynamicPorts, NomadPort{Label: label})
+		}
+	}
+
+	return network, portMap, nil
+}
+
+// nomadFirstPortLabel returns the label of the lowest container port of a task.
+func nomadFirstPortLabel(task *NomadTask) string {
+	portMap := task.Config["port_map"].([]map[string]int)[0]
+
+	first := ""
+	for label, port := range portMap {
+		if first == "" || port < portMap[first] || (port == portMap[first] && label < first) {
+			first = label
+		}
+	}
+	return first
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+type portsByNumber []nat.Port
+
+func (p portsByNumber) Len() int      { return len(p) }
+func (p portsByNumber) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p portsByNumber) Less(i, j int) bool {
+	if p[i].Int() != p[j].Int() {
+		return p[i].Int() < p[j].Int()
+	}
+	return p[i].Proto() < p[j].Proto()
+}
+
+// HCL renders the job in the HashiCorp configuration language read by nomad run.
+func (job *NomadJob) HCL() []byte {
+	w := &hclWriter{}
+
+	w.block("job", job.Name, func() {
+		w.attr("type", job.Type)
+		w.attr("datacenters", job.Datacenters)
+
+		if job.Periodic != nil {
+			w.block("periodic", "", func() {
+				w.attr("cron", job.Periodic.Spec)
+				w.attr("prohibit_overlap", job.Periodic.ProhibitOverlap)
+			})
+		}
+
+		for _, group := range job.TaskGroups {
+			w.block("group", group.Name, func() {
+				w.attr("count", group.Count)
+
+				if restart := group.RestartPolicy; restart != nil {
+					w.block("restart", "", func() {
+						w.attr("attempts", restart.Attempts)
+						w.attr("interval", time.Duration(restart.Interval).String())
+						w.attr("delay", time.Duration(restart.Delay).String())
+						w.attr("mode", restart.Mode)
+					})
+				}
+
+				for _, task := range group.Tasks {
+					w.block("task", task.Name, func() {
+						w.attr("driver", task.Driver)
+						w.block("config", "", func() {
+							keys := []string{}
+							for key := range task.Config {
+								keys = append(keys, key)
+							}
+							sort.Strings(keys)
+							for _, key := range keys {
+								switch value := task.Config[key].(type) {
+								case []map[string]int:
+									w.block(key, "", func() { w.attr("", value[0]) })
+								case []map[string]string:
+									w.block(key, "", func() { w.attr("", value[0]) })
+								default:
+									w.attr(key, value)
+								}
+							}
+						})
+
+						if len(task.Env) > 0 {
+							w.block("env", "", func() { w.attr("", task.Env) })
+						}
+
+						for _, service := range task.Services {
+							w.block("service", "", func() {
+								w.attr("name", service.Name)
+								w.attr("tags", service.Tags)
+								w.attr("port", service.PortLabel)
+								for _, check := range service.Checks {
+									w.block("check", "", func() {
+										w.attr("name", check.Name)
+										w.attr("type", check.Type)
+										w.attr("interval", time.Duration(check.Interval).String())
+										w.attr("timeout", time.Duration(check.Timeout).String())
+									})
+								}
+							})
+						}
+
+						w.block("resources", "", func() {
+							w.attr("cpu", task.Resources.CPU)
+							w.attr("memory", task.Resources.MemoryMB)
+							for _, network := range task.Resources.Networks {
+								w.block("network", "", func() {
+									w.attr("mbits", network.MBits)
+									for _, port := range network.ReservedPorts {
+										w.block("port", port.Label, func() { w.attr("static", port.Value) })
+									}
+									for _, port := range network.DynamicPorts {
+										w.line(fmt.Sprintf("port %q {}", port.Label))
+									}
+								})
+							}
+						})
+					})
+				}
+			})
+		}
+	})
+
+	return w.Bytes()
+}
+
+// hclWriter writes indented HCL blocks and attributes.
+type hclWriter struct {
+	bytes.Buffer
+	depth int
+}
+
+func (w *hclWriter) line(s string) {
+	w.WriteString(strings.Repeat("  ", w.depth))
+	w.WriteString(s)
+	w.WriteString("\n")
+}
+
+func (w *hclWriter) block(kind, label string, body func()) {
+	if label != "" {
+		w.line(fmt.Sprintf("%s %q {", kind, label))
+	} else {
+		w.line(kind + " {")
+	}
+	w.depth++
+	body()
+	w.depth--
+	w.line("}")
+}
+
+// attr writes an attribute, or the sorted entries of a map when the name is empty.
+func (w *hclWriter) attr(name string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]string:
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			w.line(fmt.Sprintf("%q = %q", key, v[key]))
+		}
+	case map[string]int:
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			w.line(fmt.Sprintf("%s = %d", key, v[key]))
+		}
+	case []string:
+		quoted := []string{}
+		for _, s := range v {
+			quoted = append(quoted, strconv.Quote(s))
+		}
+		w.line(fmt.Sprintf("%s = [%s]", name, strings.Join(quoted, ", ")))
+	case string:
+		w.line(fmt.Sprintf("%s = %q", name, v))
+	default:
+		w.line(fmt.Sprintf("%s = %v", name, v))
+	}
+}
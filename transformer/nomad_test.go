@@ -0,0 +1,103 @@
+package transformer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToNomadJob(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Command = project.NewCommand("nginx", "-g", "daemon off;")
+	p.Configs["web"].Environment = project.NewMaporEqualSlice([]string{"MODE=prod"})
+	p.Configs["web"].CPUShares = 512
+	p.Configs["web"].MemLimit = 64 * 1024 * 1024
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.autoscale.max": "4", "kompose.autoscale.min": "2"})
+	p.Configs["redis"].Expose = []string{"6379"}
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis:cache"})
+
+	job, err := ConvertToNomadJob(p)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", job.ID)
+	assert.Equal(t, 2, len(job.TaskGroups))
+
+	redis, web := job.TaskGroups[0], job.TaskGroups[1]
+	assert.Equal(t, "redis", redis.Name)
+	assert.Equal(t, 1, redis.Count)
+	assert.Equal(t, 2, web.Count)
+
+	task := web.Tasks[0]
+	assert.Equal(t, "nginx", task.Config["command"])
+	assert.Equal(t, []string{"-g", "daemon off;"}, task.Config["args"])
+	assert.Equal(t, map[string]string{"MODE": "prod"}, task.Env)
+	assert.Equal(t, 500, task.Resources.CPU)
+	assert.Equal(t, 64, task.Resources.MemoryMB)
+	assert.Equal(t, []NomadPort{{Label: "tcp80", Value: 80}}, task.Resources.Networks[0].ReservedPorts)
+	assert.Equal(t, []map[string]int{{"tcp80": 80}}, task.Config["port_map"])
+	assert.Nil(t, task.Services)
+
+	task = redis.Tasks[0]
+	assert.Equal(t, NomadDefaultCPU, task.Resources.CPU)
+	assert.Equal(t, []NomadPort{{Label: "tcp6379"}}, task.Resources.Networks[0].DynamicPorts)
+	assert.Equal(t, 2, len(task.Services))
+	assert.Equal(t, "redis", task.Services[0].Name)
+	assert.Equal(t, "cache", task.Services[1].Name)
+	assert.Equal(t, "tcp6379", task.Services[1].PortLabel)
+}
+
+func TestNomadTransform(t *testing.T) {
+	p := newTestProject()
+	p.Configs["redis"].Expose = []string{"6379"}
+
+	artifacts, err := Convert(p, "nomad", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo.nomad"}, names(artifacts))
+
+	hcl := string(artifacts[0].Data)
+	assert.Contains(t, hcl, "job \"demo\" {\n")
+	assert.Contains(t, hcl, "  group \"redis\" {\n    count = 1\n")
+	assert.Contains(t, hcl, "port \"tcp80\" {\n")
+	assert.Contains(t, hcl, "static = 80\n")
+	assert.Contains(t, hcl, "port \"tcp6379\" {}\n")
+	assert.Contains(t, hcl, "interval = \"10s\"\n")
+
+	artifacts, err = Convert(p, "nomad", Options{Format: "json"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo.nomad.json"}, names(artifacts))
+
+	job := map[string]*NomadJob{}
+	assert.Nil(t, json.Unmarshal(artifacts[0].Data, &job))
+	assert.Equal(t, "demo", job["Job"].Name)
+
+	_, err = Convert(p, "nomad", Options{Format: "yaml"})
+	assert.NotNil(t, err)
+}
+
+func TestConvertToNomadBatchJob(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.schedule": "@daily", "kompose.job.backoff-limit": "3"})
+
+	artifacts, err := Convert(p, "nomad", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo-web.nomad", "demo.nomad"}, names(artifacts))
+	assert.NotContains(t, string(artifacts[1].Data), "group \"web\"")
+
+	hcl := string(artifacts[0].Data)
+	assert.Contains(t, hcl, "job \"demo-web\" {\n  type = \"batch\"\n")
+	assert.Contains(t, hcl, "  periodic {\n    cron = \"@daily\"\n    prohibit_overlap = true\n  }\n")
+	assert.Contains(t, hcl, "    restart {\n      attempts = 3\n")
+	assert.Contains(t, hcl, "      mode = \"fail\"\n")
+
+	p.Configs["redis"].Labels = project.NewSliceorMap(map[string]string{"kompose.job": "true", "kompose.job.completions": "4"})
+	artifacts, err = Convert(p, "nomad", Options{Format: "json"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo-redis.nomad.json", "demo-web.nomad.json"}, names(artifacts))
+
+	job := map[string]*NomadJob{}
+	assert.Nil(t, json.Unmarshal(artifacts[0].Data, &job))
+	assert.Equal(t, "batch", job["Job"].Type)
+	assert.Nil(t, job["Job"].Periodic)
+	assert.Equal(t, 4, job["Job"].TaskGroups[0].Count)
+}
@@ -0,0 +1,116 @@
+package transformer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+)
+
+// cronSchedules holds the five fields of the predefined cron schedules.
+var cronSchedules = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+var cronDays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// cronFields returns the minute, hour, day of month, month and day of week
+// fields of a schedule validated by kubernetes.ParseJob.
+func cronFields(schedule string) []string {
+	if fields, ok := cronSchedules[schedule]; ok {
+		schedule = fields
+	}
+	return strings.Fields(schedule)
+}
+
+// cronRestricted reports whether a day field restricts the days a job runs.
+func cronRestricted(field string) bool {
+	return field != "*" && field != "?"
+}
+
+// convertCronField rewrites each value of a cron field, in lists, ranges and
+// steps. Steps over the whole range start at first, they are not supported if
+// first is empty. Ranges are joined with rangeSep.
+func convertCronField(field, first, rangeSep string, value func(string) (string, error)) (string, error) {
+	items := []string{}
+	for _, item := range strings.Split(field, ",") {
+		parts := strings.SplitN(item, "/", 2)
+		base := parts[0]
+
+		switch {
+		case base == "*" && len(parts) == 2:
+			if first == "" {
+				return "", fmt.Errorf("steps are not supported in %s", field)
+			}
+			var err error
+			if base, err = value(first); err != nil {
+				return "", err
+			}
+		case base == "*" || base == "?":
+		case strings.Contains(base, "-"):
+			bounds := strings.SplitN(base, "-", 2)
+			from, err := value(bounds[0])
+			if err != nil {
+				return "", err
+			}
+			to, err := value(bounds[1])
+			if err != nil {
+				return "", err
+			}
+			base = from + rangeSep + to
+		default:
+			var err error
+			if base, err = value(base); err != nil {
+				return "", err
+			}
+		}
+
+		if len(parts) == 2 {
+			base += "/" + parts[1]
+		}
+		items = append(items, base)
+	}
+	return strings.Join(items, ","), nil
+}
+
+// cronNumber returns the number of a cron value, looking up names in the
+// specified ones, numbered from offset.
+func cronNumber(value string, names []string, offset int) (int, error) {
+	for i, name := range names {
+		if strings.ToUpper(value) == name {
+			return i + offset, nil
+		}
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s", value)
+	}
+	return number, nil
+}
+
+// warnJobLabels warns about the job labels of a service the target cannot
+// express, among the ones of kubernetes.ParseJob.
+func warnJobLabels(target, name string, c *project.ServiceConfig, supported ...string) {
+	known := map[string]bool{}
+	for _, label := range supported {
+		known[label] = true
+	}
+
+	labels := c.Labels.MapParts()
+	for _, label := range []string{kubernetes.JobCompletionsLabel, kubernetes.JobParallelismLabel, kubernetes.JobBackoffLimitLabel, kubernetes.JobDeadlineLabel} {
+		if _, ok := labels[label]; ok && !known[label] {
+			logrus.Warnf("Service %s: %s is not supported by the %s target and is ignored", name, label, target)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidateSchema validates a JSON document against a JSON schema. Only the
+// subset of draft 4 used by the bundled schemas is supported: type, enum,
+// properties, required, additionalProperties, items, minItems, minimum,
+// maximum, minLength, maxLength and pattern.
+func ValidateSchema(schema, document []byte) error {
+	var s, d interface{}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("Invalid schema: %v", err)
+	}
+	if err := json.Unmarshal(document, &d); err != nil {
+		return err
+	}
+
+	schemaObject, ok := s.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Invalid schema: not an object")
+	}
+	return validate(schemaObject, d, "")
+}
+
+func validate(schema map[string]interface{}, value interface{}, path string) error {
+	at := path
+	if at == "" {
+		at = "document"
+	}
+
+	if t, ok := schema["type"].(string); ok && !hasType(value, t) {
+		return fmt.Errorf("%s must be of type %s", at, t)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		allowed := []string{}
+		for _, e := range enum {
+			if e == value {
+				found = true
+			}
+			allowed = append(allowed, fmt.Sprint(e))
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %s, got %v", at, strings.Join(allowed, ", "), value)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				if _, ok := v[r.(string)]; !ok {
+					return fmt.Errorf("%s is required", join(path, r.(string)))
+				}
+			}
+		}
+
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			property, ok := properties[key].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%s is not allowed", join(path, key))
+				}
+				if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+					property = additional
+				} else {
+					continue
+				}
+			}
+			if err := validate(property, v[key], join(path, key)); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("%s must have at least %v items", at, min)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			return fmt.Errorf("%s must be at least %v, got %v", at, min, v)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			return fmt.Errorf("%s must be at most %v, got %v", at, max, v)
+		}
+
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("%s must be at least %v characters long", at, min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && float64(len(v)) > max {
+			return fmt.Errorf("%s must be at most %v characters long", at, max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("Invalid schema pattern %s: %v", pattern, err)
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s must match %s, got %s", at, pattern, v)
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
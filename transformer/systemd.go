@@ -0,0 +1,482 @@
+package transformer
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/libcompose/docker"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+func init() {
+	Register("systemd", &Systemd{})
+}
+
+// DockerBinary is the path of the docker client run by the systemd units.
+const DockerBinary = "/usr/bin/docker"
+
+// Systemd generates a unit per service, running its container in the
+// foreground with docker run, and a target starting the whole project. The
+// units of the services labelled as jobs are oneshot ones, started by a timer
+// when the job has a schedule.
+type Systemd struct{}
+
+// SupportedKeys implements Transformer.SupportedKeys.
+func (s *Systemd) SupportedKeys() []string {
+	return []string{
+		"cap_add",
+		"cap_drop",
+		"command",
+		"container_name",
+		"cpu_shares",
+		"cpuset",
+		"devices",
+		"dns",
+		"dns_search",
+		"domainname",
+		"entrypoint",
+		"env_file",
+		"environment",
+		"expose",
+		"external_links",
+		"extra_hosts",
+		"hostname",
+		"image",
+		"ipc",
+		"labels",
+		"links",
+		"log_driver",
+		"log_opt",
+		"mem_limit",
+		"memswap_limit",
+		"net",
+		"pid",
+		"ports",
+		"privileged",
+		"read_only",
+		"restart",
+		"security_opt",
+		"stdin_open",
+		"tty",
+		"user",
+		"uts",
+		"volume_driver",
+		"volumes",
+		"volumes_from",
+		"working_dir",
+	}
+}
+
+// Transform implements Transformer.Transform.
+func (s *Systemd) Transform(p *project.Project, options Options) ([]Artifact, error) {
+	if _, err := checkFormat(options.Format, "unit"); err != nil {
+		return nil, err
+	}
+
+	artifacts := []Artifact{}
+	units := []string{}
+
+	for _, name := range ServiceNames(p) {
+		unit, err := SystemdUnit(p, name)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:    SystemdUnitName(p, name),
+			Service: name,
+			Data:    unit,
+		})
+
+		job, err := kubernetes.ParseJob(name, p.Configs[name])
+		if err != nil {
+			return nil, err
+		}
+		if job == nil || job.Schedule == "" {
+			units = append(units, SystemdUnitName(p, name))
+			continue
+		}
+
+		// The target starts the timer, which starts the job on its schedule.
+		timer, err := SystemdTimer(p, name, job)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:    SystemdTimerName(p, name),
+			Service: name,
+			Data:    timer,
+		})
+		units = append(units, SystemdTimerName(p, name))
+	}
+
+	target := &bytes.Buffer{}
+	fmt.Fprintf(target, "[Unit]\n")
+	fmt.Fprintf(target, "Description=%s compose project\n", p.Name)
+	fmt.Fprintf(target, "Wants=%s\n", strings.Join(units, " "))
+	fmt.Fprintf(target, "\n[Install]\n")
+	fmt.Fprintf(target, "WantedBy=multi-user.target\n")
+
+	artifacts = append(artifacts, Artifact{
+		Name: p.Name + ".target",
+		Data: target.Bytes(),
+	})
+
+	return artifacts, nil
+}
+
+// SystemdUnitName returns the name of the unit of a service.
+func SystemdUnitName(p *project.Project, name string) string {
+	return fmt.Sprintf("%s-%s.service", p.Name, name)
+}
+
+// SystemdTimerName returns the name of the timer of a service labelled as a
+// job with a schedule.
+func SystemdTimerName(p *project.Project, name string) string {
+	return fmt.Sprintf("%s-%s.timer", p.Name, name)
+}
+
+// containerName returns the name of the container of a service, the one of
+// its first container with the docker backend unless container_name is set.
+func containerName(p *project.Project, name string) string {
+	if c, ok := p.Configs[name]; ok && c.ContainerName != "" {
+		return c.ContainerName
+	}
+	return fmt.Sprintf("%s_%s_1", p.Name, name)
+}
+
+// SystemdUnit generates the unit of a service. It requires the units of the
+// services it depends on and is part of the target of the project. The unit of
+// a job is a oneshot one, which the target only starts if it has no schedule.
+func SystemdUnit(p *project.Project, name string) ([]byte, error) {
+	c := p.Configs[name]
+	if c.Image == "" {
+		return nil, project.NewValidationError(name, "image", "is not set, build and push the image first")
+	}
+
+	job, err := kubernetes.ParseJob(name, c)
+	if err != nil {
+		return nil, err
+	}
+	if job != nil {
+		warnJobLabels("systemd", name, c, kubernetes.JobDeadlineLabel)
+	}
+
+	args, err := DockerRunArgs(p, name)
+	if err != nil {
+		return nil, err
+	}
+
+	restart, err := runconfig.ParseRestartPolicy(c.Restart)
+	if err != nil {
+		return nil, project.NewValidationError(name, "restart", "%v", err)
+	}
+	if job != nil && (restart.IsAlways() || restart.IsUnlessStopped()) {
+		return nil, project.NewValidationError(name, "restart", "%s is not supported by jobs, use no or on-failure", c.Restart)
+	}
+
+	requires := []string{"docker.service"}
+	for _, dependency := range Dependencies(p, name) {
+		requires = append(requires, SystemdUnitName(p, dependency))
+	}
+
+	container := containerName(p, name)
+
+	unit := &bytes.Buffer{}
+	fmt.Fprintf(unit, "[Unit]\n")
+	fmt.Fprintf(unit, "Description=%s %s container\n", p.Name, name)
+	fmt.Fprintf(unit, "Requires=%s\n", strings.Join(requires, " "))
+	fmt.Fprintf(unit, "After=%s\n", strings.Join(requires, " "))
+	fmt.Fprintf(unit, "PartOf=%s.target\n", p.Name)
+	// The start rate limiting belongs to the unit section since systemd 229.
+	if restart.IsOnFailure() && restart.MaximumRetryCount > 0 {
+		fmt.Fprintf(unit, "StartLimitBurst=%d\n", restart.MaximumRetryCount)
+	}
+	fmt.Fprintf(unit, "\n[Service]\n")
+	if job != nil {
+		fmt.Fprintf(unit, "Type=oneshot\n")
+	}
+	fmt.Fprintf(unit, "ExecStartPre=-%s rm -f %s\n", DockerBinary, systemdQuote(container))
+	fmt.Fprintf(unit, "ExecStartPre=-%s pull %s\n", DockerBinary, systemdQuote(c.Image))
+	fmt.Fprintf(unit, "ExecStart=%s %s\n", DockerBinary, systemdJoin(args))
+	fmt.Fprintf(unit, "ExecStop=%s stop %s\n", DockerBinary, systemdQuote(container))
+
+	switch {
+	case restart.IsAlways(), restart.IsUnlessStopped():
+		fmt.Fprintf(unit, "Restart=always\n")
+	case restart.IsOnFailure():
+		fmt.Fprintf(unit, "Restart=on-failure\n")
+	default:
+		fmt.Fprintf(unit, "Restart=no\n")
+	}
+
+	if job != nil && job.Deadline != nil {
+		fmt.Fprintf(unit, "RuntimeMaxSec=%d\n", *job.Deadline)
+	}
+
+	// The timer of a scheduled job is installed in the target instead.
+	if job == nil || job.Schedule == "" {
+		fmt.Fprintf(unit, "\n[Install]\n")
+		fmt.Fprintf(unit, "WantedBy=%s.target\n", p.Name)
+	}
+
+	return unit.Bytes(), nil
+}
+
+// SystemdTimer generates the timer starting the unit of a service labelled as
+// a job on its schedule. It is part of the target of the project.
+func SystemdTimer(p *project.Project, name string, job *kubernetes.JobConfig) ([]byte, error) {
+	calendar, err := systemdCalendar(job.Schedule)
+	if err != nil {
+		return nil, project.NewValidationError(name, project.LabelField(kubernetes.ScheduleLabel), "%v", err)
+	}
+
+	timer := &bytes.Buffer{}
+	fmt.Fprintf(timer, "[Unit]\n")
+	fmt.Fprintf(timer, "Description=%s %s schedule\n", p.Name, name)
+	fmt.Fprintf(timer, "PartOf=%s.target\n", p.Name)
+	fmt.Fprintf(timer, "\n[Timer]\n")
+	fmt.Fprintf(timer, "OnCalendar=%s\n", calendar)
+	fmt.Fprintf(timer, "Unit=%s\n", SystemdUnitName(p, name))
+	fmt.Fprintf(timer, "\n[Install]\n")
+	fmt.Fprintf(timer, "WantedBy=%s.target\n", p.Name)
+
+	return timer.Bytes(), nil
+}
+
+// systemdCalendar converts a cron schedule to the calendar event of a timer,
+// like Mon..Fri *-*-* 03:00:00. Unlike cron, systemd runs the events matching
+// both the day of month and the day of week, so they cannot both be set.
+func systemdCalendar(schedule string) (string, error) {
+	fields := cronFields(schedule)
+	if cronRestricted(fields[2]) && cronRestricted(fields[4]) {
+		return "", fmt.Errorf("schedules setting both the day of month and the day of week are not supported by systemd timers")
+	}
+
+	padded := func(value string) (string, error) {
+		number, err := cronNumber(value, nil, 0)
+		return fmt.Sprintf("%02d", number), err
+	}
+	month := func(value string) (string, error) {
+		number, err := cronNumber(value, cronMonths, 1)
+		return fmt.Sprintf("%02d", number), err
+	}
+	day := func(value string) (string, error) {
+		number, err := cronNumber(value, cronDays, 0)
+		if err != nil || number < 0 || number > 7 {
+			return "", fmt.Errorf("invalid day of week %s", value)
+		}
+		// Sunday is 0 or 7 in cron.
+		name := cronDays[number%7]
+		return name[:1] + strings.ToLower(name[1:]), nil
+	}
+
+	minute, err := convertCronField(fields[0], "0", "..", padded)
+	if err != nil {
+		return "", err
+	}
+	hour, err := convertCronField(fields[1], "0", "..", padded)
+	if err != nil {
+		return "", err
+	}
+	dom, err := convertCronField(fields[2], "1", "..", padded)
+	if err != nil {
+		return "", err
+	}
+	months, err := convertCronField(fields[3], "1", "..", month)
+	if err != nil {
+		return "", err
+	}
+	dow, err := convertCronField(fields[4], "", "..", day)
+	if err != nil {
+		return "", err
+	}
+
+	calendar := fmt.Sprintf("*-%s-%s %s:%s:00", months, strings.Replace(dom, "?", "*", -1), hour, minute)
+	if cronRestricted(dow) {
+		calendar = dow + " " + calendar
+	}
+	return calendar, nil
+}
+
+// DockerRunArgs returns the arguments of docker run starting the container of
+// a service in the foreground, from the configuration the docker backend
+// creates it with. The restart policy is left to systemd.
+func DockerRunArgs(p *project.Project, name string) ([]string, error) {
+	c := p.Configs[name]
+
+	config, hostConfig, err := docker.Convert(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to convert service %s: %v", name, err)
+	}
+
+	args := []string{"run", "--rm", "--name", containerName(p, name)}
+	flag := func(name string, values ...string) {
+		for _, value := range values {
+			if value != "" {
+				args = append(args, name, value)
+			}
+		}
+	}
+	boolFlag := func(name string, value bool) {
+		if value {
+			args = append(args, name)
+		}
+	}
+
+	// The docker backend resolves the services referenced by the links and
+	// namespaces to their containers.
+	for _, link := range c.Links.Slice() {
+		target, alias := project.NameAlias(link)
+		if _, ok := p.Configs[target]; !ok {
+			continue
+		}
+		flag("--link", containerName(p, target)+":"+alias)
+		if alias != containerName(p, target) {
+			flag("--link", containerName(p, target)+":"+containerName(p, target))
+		}
+	}
+	flag("--link", c.ExternalLinks...)
+
+	for _, from := range hostConfig.VolumesFrom {
+		parts := strings.SplitN(from, ":", 2)
+		if _, ok := p.Configs[parts[0]]; ok {
+			parts[0] = containerName(p, parts[0])
+		}
+		flag("--volumes-from", strings.Join(parts, ":"))
+	}
+	if service := project.GetContainerFromIpcLikeConfig(p, hostConfig.NetworkMode); service != "" {
+		hostConfig.NetworkMode = "container:" + containerName(p, service)
+	}
+	if service := project.GetContainerFromIpcLikeConfig(p, hostConfig.IpcMode); service != "" {
+		hostConfig.IpcMode = "container:" + containerName(p, service)
+	}
+
+	if len(config.Entrypoint) > 0 {
+		flag("--entrypoint", config.Entrypoint[0])
+	}
+	flag("--hostname", config.Hostname)
+	flag("--domainname", config.Domainname)
+	flag("--user", config.User)
+	flag("--workdir", config.WorkingDir)
+	flag("--env", config.Env...)
+	flag("--label", sortedPairs(Labels(c))...)
+	boolFlag("--tty", config.Tty)
+	boolFlag("--interactive", config.OpenStdin)
+	flag("--volume-driver", config.VolumeDriver)
+
+	exposed := []string{}
+	for port := range config.ExposedPorts {
+		exposed = append(exposed, string(port))
+	}
+	sort.Strings(exposed)
+	flag("--expose", exposed...)
+
+	volumes := []string{}
+	for volume := range config.Volumes {
+		volumes = append(volumes, volume)
+	}
+	sort.Strings(volumes)
+	flag("--volume", volumes...)
+	flag("--volume", hostConfig.Binds...)
+
+	flag("--publish", portBindings(hostConfig.PortBindings)...)
+	flag("--cap-add", hostConfig.CapAdd...)
+	flag("--cap-drop", hostConfig.CapDrop...)
+	if hostConfig.CPUShares > 0 {
+		flag("--cpu-shares", strconv.FormatInt(hostConfig.CPUShares, 10))
+	}
+	flag("--cpuset-cpus", hostConfig.CPUSetCPUs)
+	flag("--add-host", hostConfig.ExtraHosts...)
+	boolFlag("--privileged", hostConfig.Privileged)
+	for _, device := range hostConfig.Devices {
+		flag("--device", fmt.Sprintf("%s:%s:%s", device.PathOnHost, device.PathInContainer, device.CgroupPermissions))
+	}
+	flag("--dns", hostConfig.DNS...)
+	flag("--dns-search", hostConfig.DNSSearch...)
+	flag("--log-driver", hostConfig.LogConfig.Type)
+	flag("--log-opt", sortedPairs(hostConfig.LogConfig.Config)...)
+	if hostConfig.Memory > 0 {
+		flag("--memory", strconv.FormatInt(hostConfig.Memory, 10))
+	}
+	if hostConfig.MemorySwap != 0 {
+		flag("--memory-swap", strconv.FormatInt(hostConfig.MemorySwap, 10))
+	}
+	flag("--net", hostConfig.NetworkMode)
+	boolFlag("--read-only", hostConfig.ReadonlyRootfs)
+	flag("--pid", hostConfig.PidMode)
+	flag("--uts", hostConfig.UTSMode)
+	flag("--ipc", hostConfig.IpcMode)
+	flag("--security-opt", hostConfig.SecurityOpt...)
+
+	args = append(args, config.Image)
+	if len(config.Entrypoint) > 1 {
+		args = append(args, config.Entrypoint[1:]...)
+	}
+	args = append(args, config.Cmd...)
+
+	return args, nil
+}
+
+// portBindings returns the --publish values of the bindings, sorted by
+// container port.
+func portBindings(bindings map[dockerclient.Port][]dockerclient.PortBinding) []string {
+	ports := []string{}
+	for port := range bindings {
+		ports = append(ports, string(port))
+	}
+	sort.Strings(ports)
+
+	result := []string{}
+	for _, port := range ports {
+		for _, binding := range bindings[dockerclient.Port(port)] {
+			value := port
+			if binding.HostPort != "" || binding.HostIP != "" {
+				value = binding.HostPort + ":" + port
+			}
+			if binding.HostIP != "" {
+				value = binding.HostIP + ":" + value
+			}
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func sortedPairs(m map[string]string) []string {
+	result := []string{}
+	for key, value := range m {
+		result = append(result, key+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// systemdJoin joins the arguments of a command line of a unit.
+func systemdJoin(args []string) string {
+	quoted := []string{}
+	for _, arg := range args {
+		quoted = append(quoted, systemdQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// systemdQuote quotes an argument of a command line of a unit if needed, and
+// escapes the specifiers and variables systemd would expand.
+func systemdQuote(arg string) string {
+	arg = strings.Replace(arg, "%", "%%", -1)
+	arg = strings.Replace(arg, "$", "$$", -1)
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\;") {
+		return arg
+	}
+	arg = strings.Replace(arg, `\`, `\\`, -1)
+	arg = strings.Replace(arg, `"`, `\"`, -1)
+	arg = strings.Replace(arg, "\n", `\n`, -1)
+	return `"` + arg + `"`
+}
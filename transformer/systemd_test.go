@@ -0,0 +1,117 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRunArgs(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Links = project.NewMaporColonSlice([]string{"redis:cache"})
+	p.Configs["web"].Environment = project.NewMaporEqualSlice([]string{"GREETING=hello world"})
+	p.Configs["web"].Command = project.NewCommand("nginx", "-g", "daemon off;")
+	p.Configs["web"].MemLimit = 1024
+	p.Configs["web"].Privileged = true
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"com.example.tier": "front", "kompose.autoscale.max": "2"})
+	p.Configs["redis"].Net = "container:web"
+
+	args, err := DockerRunArgs(p, "web")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"run", "--rm", "--name", "demo_web_1",
+		"--link", "demo_redis_1:cache",
+		"--link", "demo_redis_1:demo_redis_1",
+		"--env", "GREETING=hello world",
+		"--label", "com.example.tier=front",
+		"--expose", "80/tcp",
+		"--publish", "80:80/tcp",
+		"--privileged",
+		"--memory", "1024",
+		"nginx", "nginx", "-g", "daemon off;",
+	}, args)
+
+	args, err = DockerRunArgs(p, "redis")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"run", "--rm", "--name", "demo_redis_1", "--net", "container:demo_web_1", "redis"}, args)
+}
+
+func TestSystemdUnit(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Restart = "on-failure:5"
+	p.Configs["web"].Environment = project.NewMaporEqualSlice([]string{"PRICE=$5", "RATIO=50%"})
+
+	unit, err := SystemdUnit(p, "web")
+	assert.Nil(t, err)
+	assert.Contains(t, string(unit), "Requires=docker.service demo-redis.service\n")
+	assert.Contains(t, string(unit), "After=docker.service demo-redis.service\n")
+	assert.Contains(t, string(unit), "PartOf=demo.target\n")
+	assert.Contains(t, string(unit), "ExecStart=/usr/bin/docker run --rm --name demo_web_1 --link demo_redis_1:redis")
+	assert.Contains(t, string(unit), "--env PRICE=$$5 --env RATIO=50%% ")
+	assert.Contains(t, string(unit), "PartOf=demo.target\nStartLimitBurst=5\n\n[Service]\n")
+	assert.Contains(t, string(unit), "Restart=on-failure\n")
+
+	p.Configs["redis"].Image = ""
+	p.Configs["redis"].Build = "."
+	_, err = SystemdUnit(p, "redis")
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "redis.image", err.(*project.ValidationError).Path())
+	}
+}
+
+func TestSystemdTransform(t *testing.T) {
+	artifacts, err := Convert(newTestProject(), "systemd", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo-redis.service", "demo-web.service", "demo.target"}, names(artifacts))
+	assert.Contains(t, string(artifacts[2].Data), "Wants=demo-redis.service demo-web.service\n")
+	assert.Contains(t, string(artifacts[0].Data), "Restart=no\n")
+}
+
+func TestSystemdQuote(t *testing.T) {
+	assert.Equal(t, "nginx", systemdQuote("nginx"))
+	assert.Equal(t, `"daemon off;"`, systemdQuote("daemon off;"))
+	assert.Equal(t, `"say \"hi\""`, systemdQuote(`say "hi"`))
+	assert.Equal(t, `""`, systemdQuote(""))
+}
+
+func TestSystemdJobs(t *testing.T) {
+	p := newTestProject()
+	p.Configs["redis"].Labels = project.NewSliceorMap(map[string]string{"kompose.job": "true", "kompose.job.deadline": "60"})
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.schedule": "30 2 * * MON-FRI"})
+
+	artifacts, err := Convert(p, "systemd", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"demo-redis.service", "demo-web.service", "demo-web.timer", "demo.target"}, names(artifacts))
+	assert.Contains(t, string(artifacts[0].Data), "Type=oneshot\n")
+	assert.Contains(t, string(artifacts[0].Data), "RuntimeMaxSec=60\n")
+	assert.Contains(t, string(artifacts[0].Data), "WantedBy=demo.target\n")
+	assert.NotContains(t, string(artifacts[1].Data), "[Install]")
+	assert.Contains(t, string(artifacts[2].Data), "OnCalendar=Mon..Fri *-*-* 02:30:00\n")
+	assert.Contains(t, string(artifacts[3].Data), "Wants=demo-redis.service demo-web.timer\n")
+
+	p.Configs["redis"].Restart = "always"
+	_, err = SystemdUnit(p, "redis")
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "redis.restart", err.(*project.ValidationError).Path())
+	}
+}
+
+func TestSystemdCalendar(t *testing.T) {
+	for schedule, expected := range map[string]string{
+		"0 3 * * *":          "*-*-* 03:00:00",
+		"*/15 * * * *":       "*-*-* *:00/15:00",
+		"0 0 1,15 JAN-MAR *": "*-01..03-01,15 00:00:00",
+		"0 12 ? * 0,7":       "Sun,Sun *-*-* 12:00:00",
+		"@weekly":            "Sun *-*-* 00:00:00",
+	} {
+		calendar, err := systemdCalendar(schedule)
+		assert.Nil(t, err, schedule)
+		assert.Equal(t, expected, calendar, schedule)
+	}
+
+	for _, schedule := range []string{"0 0 1 * MON", "0 0 * * */2"} {
+		_, err := systemdCalendar(schedule)
+		assert.NotNil(t, err, schedule)
+	}
+}
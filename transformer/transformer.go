@@ -0,0 +1,275 @@
+package transformer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/kubernetes"
+	"github.com/docker/libcompose/project"
+	"github.com/ghodss/yaml"
+)
+
+// Artifact is a file generated from a project by a transformer.
+type Artifact struct {
+	// Name is the path of the file, relative to the output directory.
+	Name string
+	// Service is the compose service the artifact was generated from, empty
+	// for the artifacts of the whole project.
+	Service string
+	Data    []byte
+	// Mode is the permission of the file, 0644 if not set.
+	Mode os.FileMode
+}
+
+// Options holds the conversion options. Targets ignore the options they do
+// not support.
+type Options struct {
+	// Format is the output format, among the ones supported by the target,
+	// the first one of these if empty.
+	Format string
+	// Deployment generates deployments rather than replication controllers.
+	Deployment bool
+	// WaitForDeps delays the start of services until their links accept connections.
+	WaitForDeps bool
+	// NetworkPolicies restricts the connections to the services to their links.
+	NetworkPolicies bool
+	// PullSecrets generates the credentials of the private registries found
+	// in the docker configuration of ConfigDir.
+	PullSecrets bool
+	ConfigDir   string
+}
+
+// Transformer converts a compose project to the objects of an orchestrator.
+type Transformer interface {
+	// Transform generates the artifacts of the services of the project.
+	Transform(p *project.Project, options Options) ([]Artifact, error)
+	// SupportedKeys returns the compose keys handled by the transformer,
+	// the other ones are reported and ignored.
+	SupportedKeys() []string
+}
+
+var transformers = map[string]Transformer{}
+
+// Register makes a transformer available under the specified target name.
+func Register(target string, t Transformer) {
+	if _, ok := transformers[target]; ok {
+		panic(fmt.Sprintf("Transformer %s is already registered", target))
+	}
+	transformers[target] = t
+}
+
+// Get returns the transformer of the specified target.
+func Get(target string) (Transformer, error) {
+	t, ok := transformers[target]
+	if !ok {
+		return nil, fmt.Errorf("Unknown target %s, expected one of %s", target, strings.Join(Targets(), ", "))
+	}
+	return t, nil
+}
+
+// Targets returns the sorted names of the registered transformers.
+func Targets() []string {
+	targets := []string{}
+	for target := range transformers {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Convert transforms the project with the transformer of the target, after
+// checking the kompose labels of the services and warning about the keys the
+// target does not support. The artifacts are sorted by name.
+func Convert(p *project.Project, target string, options Options) ([]Artifact, error) {
+	t, err := Get(target)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range ServiceNames(p) {
+		if err := kubernetes.ValidateDirectives(name, p.Configs[name]); err != nil {
+			return nil, err
+		}
+		for _, key := range UnsupportedKeys(p.Configs[name], t.SupportedKeys()) {
+			logrus.Warnf("Service %s: %s is not supported by the %s target and is ignored", name, key, target)
+		}
+	}
+
+	artifacts, err := t.Transform(p, options)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(artifactsByName(artifacts))
+	for i := 1; i < len(artifacts); i++ {
+		if artifacts[i].Name == artifacts[i-1].Name {
+			return nil, fmt.Errorf("Several artifacts are named %s", artifacts[i].Name)
+		}
+	}
+
+	return artifacts, nil
+}
+
+// Write writes the artifacts to the output directory, creating it if needed.
+func Write(dir string, artifacts []Artifact) error {
+	for _, artifact := range artifacts {
+		file := filepath.Join(dir, artifact.Name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+
+		mode := artifact.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+
+		if err := ioutil.WriteFile(file, artifact.Data, mode); err != nil {
+			return err
+		}
+		logrus.Infof("Wrote %s", file)
+	}
+	return nil
+}
+
+// ServiceNames returns the sorted names of the services of the project.
+func ServiceNames(p *project.Project) []string {
+	names := []string{}
+	for name := range p.Configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dependencies returns the sorted services of the project a service depends
+// on, through links, volumes_from, net or ipc.
+func Dependencies(p *project.Project, name string) []string {
+	return kubernetes.DependentServices(p, name)
+}
+
+// Environment returns the environment variables of a service by name, parsed
+// like the kubernetes ones.
+func Environment(name string, c *project.ServiceConfig) (map[string]string, error) {
+	envs, err := kubernetes.ParseEnvironment(name, c)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for _, e := range envs {
+		env[e.Name] = e.Value
+	}
+	return env, nil
+}
+
+// Labels returns the labels of a service to set on its containers, without the
+// kompose directives, which are only read by the transformers.
+func Labels(c *project.ServiceConfig) map[string]string {
+	labels := map[string]string{}
+	for key, value := range c.Labels.MapParts() {
+		if !strings.HasPrefix(key, kubernetes.DirectivePrefix) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// UnsupportedKeys returns the compose keys set on the service which are not
+// part of the supported ones, in the order of the compose reference.
+func UnsupportedKeys(c *project.ServiceConfig, supported []string) []string {
+	known := map[string]bool{}
+	for _, key := range supported {
+		known[key] = true
+	}
+
+	result := []string{}
+	value := reflect.ValueOf(*c)
+	for i := 0; i < value.NumField(); i++ {
+		key := strings.Split(value.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || known[key] {
+			continue
+		}
+		if isEmpty(value.Field(i)) {
+			continue
+		}
+		result = append(result, key)
+	}
+	return result
+}
+
+// isEmpty returns true if the value is unset, the compose types wrapping
+// slices and maps being empty when their parts are.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isEmpty(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// checkFormat returns the format of the options, the first one of the
+// supported formats if empty.
+func checkFormat(format string, supported ...string) (string, error) {
+	if format == "" {
+		return supported[0], nil
+	}
+	for _, f := range supported {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("Unknown format %s, expected %s", format, strings.Join(supported, " or "))
+}
+
+// Marshal encodes an object in the specified format, json or yaml, and returns
+// it along with the file extension of the format.
+func Marshal(v interface{}, format string) ([]byte, string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, "", err
+	}
+	return Reformat(data, format)
+}
+
+// Reformat converts JSON to the specified format, json or yaml, and returns it
+// along with the file extension of the format.
+func Reformat(data []byte, format string) ([]byte, string, error) {
+	if format == "yaml" {
+		data, err := yaml.JSONToYAML(data)
+		return data, "yaml", err
+	}
+
+	indented := &bytes.Buffer{}
+	if err := json.Indent(indented, data, "", "  "); err != nil {
+		return nil, "", err
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), "json", nil
+}
+
+type artifactsByName []Artifact
+
+func (a artifactsByName) Len() int           { return len(a) }
+func (a artifactsByName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a artifactsByName) Less(i, j int) bool { return a[i].Name < a[j].Name }
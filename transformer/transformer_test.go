@@ -0,0 +1,157 @@
+package transformer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProject() *project.Project {
+	p := project.NewProject(&project.Context{})
+	p.Name = "demo"
+	p.Configs["web"] = &project.ServiceConfig{
+		Image: "nginx",
+		Ports: []string{"80:80"},
+		Links: project.NewMaporColonSlice([]string{"redis"}),
+	}
+	p.Configs["redis"] = &project.ServiceConfig{Image: "redis"}
+	return p
+}
+
+func names(artifacts []Artifact) []string {
+	result := []string{}
+	for _, artifact := range artifacts {
+		result = append(result, artifact.Name)
+	}
+	return result
+}
+
+func TestGet(t *testing.T) {
+	transformer, err := Get("kubernetes")
+	assert.Nil(t, err)
+	assert.NotNil(t, transformer)
+
+	_, err = Get("unknown")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "kubernetes")
+}
+
+func TestUnsupportedKeys(t *testing.T) {
+	c := &project.ServiceConfig{
+		Image:       "nginx",
+		Build:       ".",
+		Privileged:  true,
+		DNS:         project.NewStringorslice("8.8.8.8"),
+		Environment: project.NewMaporEqualSlice([]string{}),
+	}
+
+	assert.Equal(t, []string{"build", "dns", "privileged"}, UnsupportedKeys(c, []string{"image", "environment"}))
+	assert.Equal(t, []string{"dns"}, UnsupportedKeys(c, []string{"image", "build", "privileged"}))
+}
+
+func TestEnvironmentAndLabels(t *testing.T) {
+	c := &project.ServiceConfig{
+		Environment: project.NewMaporEqualSlice([]string{"DEBUG=1", "URL=http://a/?b=c", "MODE: 'prod'"}),
+		Labels:      project.NewSliceorMap(map[string]string{"team": "web", "kompose.job": "true"}),
+	}
+
+	env, err := Environment("web", c)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"DEBUG": "1", "URL": "http://a/?b=c", "MODE": "prod"}, env)
+	assert.Equal(t, map[string]string{"team": "web"}, Labels(c))
+
+	c.Environment = project.NewMaporEqualSlice([]string{"DEBUG"})
+	_, err = Environment("web", c)
+	if assert.IsType(t, &project.ValidationError{}, err) {
+		assert.Equal(t, "web.environment[0]", err.(*project.ValidationError).Path())
+	}
+}
+
+func TestConvertKubernetes(t *testing.T) {
+	artifacts, err := Convert(newTestProject(), "kubernetes", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"redis-rc.json", "web-rc.json", "web-svc.json"}, names(artifacts))
+	assert.Equal(t, "web", artifacts[1].Service)
+
+	rc := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(artifacts[1].Data, &rc))
+	assert.Equal(t, "ReplicationController", rc["kind"])
+
+	artifacts, err = Convert(newTestProject(), "kubernetes", Options{Format: "yaml", Deployment: true, NetworkPolicies: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"redis-deployment.yaml",
+		"redis-networkpolicy.yaml",
+		"web-deployment.yaml",
+		"web-networkpolicy.yaml",
+		"web-svc.yaml",
+	}, names(artifacts))
+
+	_, err = Convert(newTestProject(), "kubernetes", Options{Format: "xml"})
+	assert.NotNil(t, err)
+}
+
+func TestConvertKubernetesJobs(t *testing.T) {
+	p := newTestProject()
+	p.Configs["migrate"] = &project.ServiceConfig{
+		Image:  "web",
+		Ports:  []string{"8080"},
+		Labels: project.NewSliceorMap(map[string]string{"kompose.job": "true"}),
+	}
+	p.Configs["report"] = &project.ServiceConfig{
+		Image:  "web",
+		Labels: project.NewSliceorMap(map[string]string{"kompose.schedule": "0 3 * * *"}),
+	}
+
+	artifacts, err := Convert(p, "kubernetes", Options{Deployment: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"migrate-job.json",
+		"redis-deployment.json",
+		"report-cronjob.json",
+		"web-deployment.json",
+		"web-svc.json",
+	}, names(artifacts))
+
+	cronJob := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(artifacts[2].Data, &cronJob))
+	assert.Equal(t, "CronJob", cronJob["kind"])
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kompose")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	err = Write(filepath.Join(dir, "out"), []Artifact{
+		{Name: "web.json", Data: []byte("{}")},
+		{Name: "secrets/registry.json", Data: []byte("{}"), Mode: 0600},
+	})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "out", "web.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", string(data))
+
+	info, err := os.Stat(filepath.Join(dir, "out", "secrets", "registry.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestConvertUnknownDirective(t *testing.T) {
+	p := newTestProject()
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.constraint": "hostname:UNIQUE"})
+
+	_, err := Convert(p, "systemd", Options{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "kompose.constraint")
+
+	p.Configs["web"].Labels = project.NewSliceorMap(map[string]string{"kompose.constraints": "hostname:UNIQUE", "service": "web"})
+	_, err = Convert(p, "marathon", Options{})
+	assert.Nil(t, err)
+}